@@ -47,6 +47,33 @@ func (command ConfigCommand) configParser(c *core.Config, fields []string) (any,
 		} else {
 			return nil, FailedParsing
 		}
+	} else if fields[0] == "supervisor" {
+
+		if numOfFields < 2 {
+			return nil, FailedParsing
+		}
+
+		if fields[1] == "cancel-timeout" {
+			return &c.Supervisor.CancelTimeout, nil
+		} else if fields[1] == "heartbeat-timeout" {
+			return &c.Supervisor.HeartbeatTimeout, nil
+		} else if fields[1] == "idempotency-window" {
+			return &c.Supervisor.IdempotencyWindow, nil
+		} else if (fields[1] == "retention") && (numOfFields > 2) {
+			switch fields[2] {
+			case "max-age":
+				return &c.Supervisor.Retention.MaxAge, nil
+			case "max-per-cluster":
+				return &c.Supervisor.Retention.MaxPerCluster, nil
+			case "collect-every":
+				return &c.Supervisor.Retention.CollectEvery, nil
+			case "archive":
+				return &c.Supervisor.Retention.Archive, nil
+			}
+			return nil, FailedParsing
+		} else {
+			return nil, FailedParsing
+		}
 	} else if fields[0] == "mount-by-default" {
 		return &c.MountByDefault, nil
 	} else if fields[0] == "repl" {
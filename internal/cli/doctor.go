@@ -50,6 +50,18 @@ func (dc DoctorCommand) Run(cl *commandline.CommandLine) commandline.TerminateOn
 		fmt.Printf("[✓] the messenger folder exists (%s)\n", common.DefaultMessengerFolder)
 	}
 
+	if _, err := os.Stat(common.DefaultHistoryFolder); err != nil {
+		fmt.Printf("[x] the history folder is missing (%s)\n", common.DefaultHistoryFolder)
+	} else {
+		fmt.Printf("[✓] the history folder exists (%s)\n", common.DefaultHistoryFolder)
+	}
+
+	if _, err := os.Stat(common.DefaultSupervisorFolder); err != nil {
+		fmt.Printf("[x] the supervisors folder is missing (%s)\n", common.DefaultSupervisorFolder)
+	} else {
+		fmt.Printf("[✓] the supervisors folder exists (%s)\n", common.DefaultSupervisorFolder)
+	}
+
 	if _, err := os.Stat(common.DefaultConfigFile); err != nil {
 		fmt.Printf("[x] the global common file is missing (%s)\n", common.DefaultConfigFile)
 		return commandline.Terminate
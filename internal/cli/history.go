@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/GabeCordo/cluster-tools/internal/core/components/database"
+	"github.com/GabeCordo/cluster-tools/internal/core/threads/common"
+	"github.com/GabeCordo/commandline"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type HistoryController struct {
+}
+
+func (controller HistoryController) Run(cli *commandline.CommandLine) commandline.TerminateOnCompletion {
+
+	identifier := cli.NextArg()
+
+	if identifier == commandline.FinalArg {
+
+		// there is no job specified, output the latest run of every job
+		files, _ := filepath.Glob(filepath.Join(common.DefaultHistoryFolder, "*.json"))
+		for _, file := range files {
+
+			runs, err := readHistory(file)
+			if (err != nil) || (len(runs) == 0) {
+				continue
+			}
+
+			last := runs[len(runs)-1]
+			fmt.Printf("├─ %s\t(runs: %d, last: %s %s)\n", strings.TrimSuffix(filepath.Base(file), ".json"),
+				len(runs), last.Scheduled.Format(time.RFC822), last.Status)
+		}
+	} else {
+
+		// the operator specified a job, output each of its runs
+		runs, err := readHistory(filepath.Join(common.DefaultHistoryFolder, identifier+".json"))
+		if err != nil {
+			fmt.Printf("the job has no run history (%s)\n", identifier)
+			return commandline.Terminate
+		}
+
+		for _, run := range runs {
+
+			fmt.Printf("├─ %s\tsupervisor: %d\tstatus: %s", run.Scheduled.Format(time.RFC822), run.Supervisor, run.Status)
+			if !run.Finished.IsZero() {
+				fmt.Printf("\tduration: %s", run.Duration)
+			}
+			if run.Error != "" {
+				fmt.Printf("\terror: %s", run.Error)
+			}
+			fmt.Println()
+		}
+	}
+
+	return commandline.Terminate
+}
+
+func readHistory(path string) ([]database.Run, error) {
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]database.Run, 0)
+	err = json.Unmarshal(b, &runs)
+
+	return runs, err
+}
@@ -70,6 +70,20 @@ func (ic InitCommand) Run(cli *commandline.CommandLine) commandline.TerminateOnC
 		fmt.Printf("[✓] created scheduels folder %s\n", common.DefaultMessengerFolder)
 	}
 
+	if err := os.Mkdir(common.DefaultHistoryFolder, 0700); err != nil {
+		fmt.Printf("[x] failed to create %s directory %s\n", common.DefaultHistoryFolder, err.Error())
+		return commandline.Terminate
+	} else {
+		fmt.Printf("[✓] created history folder %s\n", common.DefaultHistoryFolder)
+	}
+
+	if err := os.Mkdir(common.DefaultSupervisorFolder, 0700); err != nil {
+		fmt.Printf("[x] failed to create %s directory %s\n", common.DefaultSupervisorFolder, err.Error())
+		return commandline.Terminate
+	} else {
+		fmt.Printf("[✓] created supervisors folder %s\n", common.DefaultSupervisorFolder)
+	}
+
 	if err := os.Mkdir(common.DefaultConfigsFolder, 0700); err != nil {
 		fmt.Printf("[x] failed to create %s directory %s\n", common.DefaultConfigsFolder, err.Error())
 		return commandline.Terminate
@@ -2,13 +2,14 @@ package cli
 
 import (
 	"fmt"
+	"github.com/GabeCordo/cluster-tools/internal/core/components/database"
 	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
 	"github.com/GabeCordo/cluster-tools/internal/core/threads/common"
 	"github.com/GabeCordo/commandline"
-	"gopkg.in/yaml.v3"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type ScheduleController struct {
@@ -46,6 +47,16 @@ func (controller ScheduleController) Run(cli *commandline.CommandLine) commandli
 		return commandline.Terminate
 	}
 
+	// the static schedule can be inspected without changing it
+	switch job.Identifier {
+	case "list":
+		return controller.list()
+	case "validate":
+		return controller.validate()
+	case "next":
+		return controller.next(cli)
+	}
+
 	// the module identifier is required by both the CREATE and DELETE flags
 	job.Module = cli.NextArg()
 	if job.Module == commandline.FinalArg {
@@ -69,83 +80,85 @@ func (controller ScheduleController) Run(cli *commandline.CommandLine) commandli
 			return commandline.Terminate
 		}
 
-		minutes := cli.NextArg()
-		if minutes == commandline.FinalArg {
-			fmt.Println("missing the minutes parameter")
+		schedule := cli.NextArg()
+		if schedule == commandline.FinalArg {
+			fmt.Println("missing the schedule parameter")
 			return commandline.Terminate
-		} else {
-			job.Interval.Minute = controller.ParseTime(minutes)
 		}
-	}
-
-	dump := &interfaces.Dump{}
 
-	filePath := fmt.Sprintf("%s/%s.yml", common.DefaultSchedulesFolder, job.Module)
-	if _, err := os.Stat(filePath); os.IsNotExist(err) && cli.Flag(commandline.Delete) {
-		fmt.Printf("cannot delete schedule file that does not exist(%s/%s.yml)\n",
-			common.DefaultSchedulesFolder, job.Module)
-		return commandline.Terminate
-	} else if err == nil {
-		fmt.Println("[-] schedule file exists ... pulling data")
-		if b, err := os.ReadFile(filePath); err == nil {
-			if err = yaml.Unmarshal(b, dump); err != nil {
-				fmt.Println(err)
-			}
+		// the legacy '-' and '-/n' minute notation is still accepted, a timestamp
+		// creates a job that runs once, anything else is treated as a crontab
+		// expression (ex. "0 2 * * *" or @daily)
+		if strings.HasPrefix(schedule, "-") {
+			job.Interval.Minute = controller.ParseTime(schedule)
+		} else if runAt, err := time.Parse(time.RFC3339, schedule); err == nil {
+			job.RunAt = runAt
 		} else {
+			job.Schedule = schedule
+		}
+
+		if err := job.Validate(); err != nil {
 			fmt.Println(err)
+			return commandline.Terminate
 		}
 	}
 
-	// DETERMINE IF THE MODULE FILE EXISTS IN THE SCHEDULER FOLDER
-
-	if cli.Flag(commandline.Create) {
-
-		if dump.Jobs == nil {
-			dump.Jobs = make([]interfaces.Job, 0)
-		}
-		dump.Jobs = append(dump.Jobs, job)
+	// a job can be paused or resumed without losing its definition
+	action := ""
+	if cli.Flag(commandline.Update) {
 
-		b, err := yaml.Marshal(dump)
-		if err != nil {
+		action = cli.NextArg()
+		if (action != "pause") && (action != "resume") {
+			fmt.Println("expected the pause or resume parameter")
 			return commandline.Terminate
 		}
+	}
 
-		err = os.WriteFile(filePath, b, 0750)
-		if err != nil {
-			fmt.Println(err)
-			return commandline.Terminate
-		}
+	filePath := fmt.Sprintf("%s/%s.yml", common.DefaultSchedulesFolder, job.Module)
+	if _, err := os.Stat(filePath); os.IsNotExist(err) && (cli.Flag(commandline.Delete) || cli.Flag(commandline.Update)) {
+		fmt.Printf("cannot modify schedule file that does not exist(%s/%s.yml)\n",
+			common.DefaultSchedulesFolder, job.Module)
+		return commandline.Terminate
+	}
 
-		fmt.Println("[-] added new job to static schedule file successfully")
+	// the file is written the same way the core saves it, so the core and
+	// cli never see a partially written schedule file
+	found := false
+	err := database.WriteScheduleFile(filePath, func(dump *interfaces.Dump) {
 
-	} else {
+		if cli.Flag(commandline.Create) {
+			dump.Jobs = append(dump.Jobs, job)
+			return
+		}
 
-		modifiedJobsList := make([]interfaces.Job, 0)
+		modifiedJobsList := make([]interfaces.Job, 0, len(dump.Jobs))
 		for _, j := range dump.Jobs {
 			if j.Identifier != job.Identifier {
 				modifiedJobsList = append(modifiedJobsList, j)
+				continue
 			}
-		}
-
-		fmt.Println("[-] removed job from static schedule successfully")
-
-		if err := os.Remove(filePath); err != nil {
-			return commandline.Terminate
-		}
-
-		if len(modifiedJobsList) > 0 {
-			fmt.Println("[-] saving changes to static schedules file ...")
-
-			b, err := yaml.Marshal(modifiedJobsList)
-			if err != nil {
-				fmt.Println(err)
-				return commandline.Terminate
+			found = true
+			if cli.Flag(commandline.Update) {
+				j.Paused = action == "pause"
+				modifiedJobsList = append(modifiedJobsList, j)
 			}
+		}
+		dump.Jobs = modifiedJobsList
+	})
 
-			err = os.WriteFile(filePath, b, 0750)
+	if err != nil {
+		fmt.Println(err)
+		return commandline.Terminate
+	}
 
-			fmt.Println("[-] updated static schedule successfully")
-		}
+	if cli.Flag(commandline.Create) {
+		fmt.Println("[-] added new job to static schedule file successfully")
+	} else if !found {
+		fmt.Printf("the job %s does not exist in the static schedule\n", job.Identifier)
+	} else if cli.Flag(commandline.Update) {
+		fmt.Printf("[-] %sd job in static schedule successfully\n", action)
+	} else {
+		fmt.Println("[-] removed job from static schedule successfully")
 	}
 
 	return commandline.Terminate
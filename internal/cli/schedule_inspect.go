@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/GabeCordo/cluster-tools/internal/core/components/database"
+	"github.com/GabeCordo/cluster-tools/internal/core/components/scheduler"
+	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+	"github.com/GabeCordo/cluster-tools/internal/core/threads/common"
+	"github.com/GabeCordo/commandline"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultUpcomingCount = 5
+)
+
+// list
+// Outputs every job and blackout window in the schedules folder, grouped by file.
+func (controller ScheduleController) list() commandline.TerminateOnCompletion {
+
+	files, err := database.ReadSchedules(common.DefaultSchedulesFolder)
+	if err != nil {
+		fmt.Println(err)
+		return commandline.Terminate
+	}
+
+	for _, file := range files {
+
+		fmt.Printf("%s\n", filepath.Base(file.Path))
+		for _, job := range file.Dump.Jobs {
+			fmt.Printf("├─ %s\n", job.ToString())
+		}
+		for _, blackout := range file.Dump.Blackouts {
+			fmt.Printf("├─ blackout %s\n", blackout.ToString())
+		}
+	}
+
+	return commandline.Terminate
+}
+
+// validate
+// Checks every file in the schedules folder the way the core would when it loads them,
+// unlike the core every problem is reported instead of stopping on the first.
+func (controller ScheduleController) validate() commandline.TerminateOnCompletion {
+
+	files, err := database.ReadSchedules(common.DefaultSchedulesFolder)
+	if err != nil {
+		fmt.Println(err)
+		return commandline.Terminate
+	}
+
+	problems := 0
+	report := func(file string, format string, a ...any) {
+		fmt.Printf("├─ %s: %s\n", filepath.Base(file), fmt.Sprintf(format, a...))
+		problems++
+	}
+
+	jobs := make([]interfaces.Job, 0)
+	defined := make(map[string]string)
+
+	for _, file := range files {
+
+		for _, job := range file.Dump.Jobs {
+
+			if err := job.Validate(); err != nil {
+				report(file.Path, "job %s %s", job.Identifier, err.Error())
+			}
+
+			if other, found := defined[job.Identifier]; found {
+				report(file.Path, "job %s is already defined in %s", job.Identifier, filepath.Base(other))
+				continue
+			}
+			defined[job.Identifier] = file.Path
+			jobs = append(jobs, job)
+		}
+
+		for _, blackout := range file.Dump.Blackouts {
+			if err := blackout.Validate(); err != nil {
+				report(file.Path, "blackout window %s %s", blackout.Name, err.Error())
+			}
+		}
+	}
+
+	if err := scheduler.CheckDependencies(jobs); err != nil {
+		fmt.Printf("├─ %s\n", err.Error())
+		problems++
+	}
+
+	if problems > 0 {
+		fmt.Printf("[x] found %d problem(s) in the static schedule\n", problems)
+	} else {
+		fmt.Printf("[-] the static schedule is valid (jobs: %d)\n", len(jobs))
+	}
+
+	return commandline.Terminate
+}
+
+// next
+// Outputs the upcoming times each job in the schedules folder will fire on.
+func (controller ScheduleController) next(cli *commandline.CommandLine) commandline.TerminateOnCompletion {
+
+	count := DefaultUpcomingCount
+
+	// the count can be passed as '--count N' or just 'N'
+	if arg := cli.NextArg(); arg != commandline.FinalArg {
+		if (arg == "--count") || (arg == "-count") {
+			arg = cli.NextArg()
+		}
+		n, err := strconv.Atoi(arg)
+		if (err != nil) || (n <= 0) {
+			fmt.Println("expected the count to be a positive number")
+			return commandline.Terminate
+		}
+		count = n
+	}
+
+	files, err := database.ReadSchedules(common.DefaultSchedulesFolder)
+	if err != nil {
+		fmt.Println(err)
+		return commandline.Terminate
+	}
+
+	blackouts := make([]interfaces.Blackout, 0)
+	for _, file := range files {
+		blackouts = append(blackouts, file.Dump.Blackouts...)
+	}
+
+	now := time.Now()
+	for _, file := range files {
+		for _, job := range file.Dump.Jobs {
+
+			fmt.Printf("%s\n", job.ToString())
+			if job.Paused {
+				continue
+			}
+
+			for _, next := range job.Upcoming(now, count) {
+				fmt.Printf("├─ %s", next.Format(time.RFC1123))
+				for _, blackout := range blackouts {
+					if open, _ := blackout.Until(next); open && blackout.Covers(&job) {
+						fmt.Printf("\t(blackout: %s)", blackout.Name)
+					}
+				}
+				fmt.Println()
+			}
+		}
+	}
+
+	return commandline.Terminate
+}
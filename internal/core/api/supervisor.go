@@ -2,11 +2,19 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/GabeCordo/cluster-tools/internal/core/components/supervisor"
 	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
 	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	FindTimeout = 5 // seconds
 )
 
 var client = http.Client{}
@@ -45,3 +53,90 @@ func ProvisionSupervisor(processor string, moduleName, clusterName string, super
 	}
 	return nil
 }
+
+// CancelSupervisor
+// Asks the processor running the supervisor to stop it, the processor confirms the
+// supervisor stopped by updating it to the cancelled status.
+func CancelSupervisor(processor string, supervisor uint64) error {
+
+	url := fmt.Sprintf("http://%s/supervisor?id=%d", processor, supervisor)
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+
+	rsp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return errors.New("failed to cancel supervisor")
+	}
+	return nil
+}
+
+// FindSupervisor
+// Asks the processor if it is still running the supervisor, returns an error if the
+// processor can not be reached in time or does not know the supervisor.
+func FindSupervisor(processor string, supervisor uint64) error {
+
+	ctx, cancel := context.WithTimeout(context.Background(), FindTimeout*time.Second)
+	defer cancel()
+
+	url := fmt.Sprintf("http://%s/supervisor?id=%d", processor, supervisor)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	rsp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return errors.New("processor is not running the supervisor")
+	}
+	return nil
+}
+
+// GetSupervisors
+// Asks the core for the supervisors the query selects. A query with a limit or a cursor is
+// answered with a page whose next cursor is empty on the last page, any other query is
+// answered with every supervisor it selects and must have at least one filter.
+func GetSupervisors(core string, query url.Values) (page supervisor.Page, err error) {
+
+	endpoint := fmt.Sprintf("http://%s/supervisor?%s", core, query.Encode())
+	rsp, err := client.Get(endpoint)
+	if err != nil {
+		return page, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return page, fmt.Errorf("failed to query supervisors (status: %d)", rsp.StatusCode)
+	}
+
+	body := struct {
+		interfaces.HTTPResponse
+		Data json.RawMessage `json:"data"`
+	}{}
+	if err = json.NewDecoder(rsp.Body).Decode(&body); err != nil {
+		return page, err
+	}
+
+	if !body.Success {
+		return page, errors.New(body.Description)
+	}
+
+	if query.Has("limit") || query.Has("cursor") {
+		err = json.Unmarshal(body.Data, &page)
+	} else {
+		err = json.Unmarshal(body.Data, &page.Supervisors)
+	}
+
+	return page, err
+}
@@ -1,7 +1,41 @@
 package database
 
+import (
+	"os"
+	"path/filepath"
+)
+
 type Database interface {
 	Save(path string) error
 	Load(path string) error
 	Print()
 }
+
+// writeFile
+// Replaces the file at the path with the bytes through a temporary file in the same folder,
+// the file is either left as it was or fully written if the core stops part way through.
+// The temporary file starts with a dot so it is never read back as a record.
+func writeFile(path string, b []byte) error {
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once the file is renamed
+
+	if _, err = tmp.Write(b); err == nil {
+		err = tmp.Sync()
+	}
+	if e := tmp.Close(); err == nil {
+		err = e
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = os.Chmod(tmp.Name(), 0750); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
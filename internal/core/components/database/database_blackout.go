@@ -0,0 +1,11 @@
+package database
+
+import (
+	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+)
+
+type BlackoutDatabase interface {
+	GetAll() ([]interfaces.Blackout, error)
+	Create(blackout *interfaces.Blackout) error
+	Delete(name string) error
+}
@@ -0,0 +1,166 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// BlackoutsFile
+// The file in the schedules folder blackout windows created at runtime are saved to.
+const BlackoutsFile = "blackouts.yml"
+
+type LocalBlackoutDatabase struct {
+	blackouts []interfaces.Blackout
+	files     map[string]string // the schedule file each window is saved to, by name
+	removed   map[string]bool   // the windows deleted since the schedule files were last saved
+	mutex     sync.RWMutex
+}
+
+func NewLocalBlackoutDatabase() *LocalBlackoutDatabase {
+	db := new(LocalBlackoutDatabase)
+	db.blackouts = make([]interfaces.Blackout, 0)
+	db.files = make(map[string]string)
+	db.removed = make(map[string]bool)
+	return db
+}
+
+// Load
+// Loads the blackout windows defined in the yaml files of the schedules folder,
+// windows can be defined beside the jobs they apply to.
+func (db *LocalBlackoutDatabase) Load(path string) error {
+
+	files, err := ReadSchedules(path)
+	if err != nil {
+		return err
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	for _, file := range files {
+		for _, blackout := range file.Dump.Blackouts {
+			if err = blackout.Validate(); err != nil {
+				return fmt.Errorf("blackout window %s could not be loaded: %w", blackout.Name, err)
+			}
+			db.blackouts = append(db.blackouts, blackout)
+			db.files[blackout.Name] = file.Path
+		}
+	}
+
+	return nil
+}
+
+// Save
+// Writes every blackout window back into the schedule file it was loaded from, windows
+// created at runtime are saved into the blackouts file. The jobs in each file are kept.
+func (db *LocalBlackoutDatabase) Save(path string) error {
+
+	if err := os.MkdirAll(path, 0750); err != nil {
+		return err
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	known := make(map[string]bool)
+	grouped := make(map[string][]interfaces.Blackout)
+
+	// files that lost all their windows still need to be rewritten
+	for _, file := range db.files {
+		grouped[file] = make([]interfaces.Blackout, 0)
+	}
+
+	for _, blackout := range db.blackouts {
+		file, found := db.files[blackout.Name]
+		if !found {
+			file = filepath.Join(path, BlackoutsFile)
+			db.files[blackout.Name] = file
+		}
+		grouped[file] = append(grouped[file], blackout)
+		known[blackout.Name] = true
+	}
+
+	for file, blackouts := range grouped {
+
+		err := WriteScheduleFile(file, func(dump *interfaces.Dump) {
+
+			merged := make([]interfaces.Blackout, 0, len(dump.Blackouts)+len(blackouts))
+			for _, blackout := range dump.Blackouts {
+				if !known[blackout.Name] && !db.removed[blackout.Name] {
+					merged = append(merged, blackout)
+				}
+			}
+			dump.Blackouts = append(merged, blackouts...)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for name := range db.removed {
+		if !known[name] {
+			delete(db.files, name)
+		}
+	}
+	db.removed = make(map[string]bool)
+
+	return nil
+}
+
+func (db *LocalBlackoutDatabase) Print() {
+
+	for _, blackout := range db.blackouts {
+		fmt.Printf("├─ %s\n", blackout.ToString())
+	}
+}
+
+func (db *LocalBlackoutDatabase) GetAll() ([]interfaces.Blackout, error) {
+
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	blackouts := make([]interfaces.Blackout, len(db.blackouts))
+	copy(blackouts, db.blackouts)
+
+	return blackouts, nil
+}
+
+func (db *LocalBlackoutDatabase) Create(blackout *interfaces.Blackout) error {
+
+	if err := blackout.Validate(); err != nil {
+		return err
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	for _, existing := range db.blackouts {
+		if existing.Name == blackout.Name {
+			return errors.New("blackout window with the same name already exists")
+		}
+	}
+
+	db.blackouts = append(db.blackouts, *blackout)
+
+	return nil
+}
+
+func (db *LocalBlackoutDatabase) Delete(name string) error {
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	for idx, blackout := range db.blackouts {
+		if blackout.Name == name {
+			db.blackouts = append(db.blackouts[:idx], db.blackouts[idx+1:]...)
+			db.removed[name] = true
+			return nil
+		}
+	}
+
+	return interfaces.BlackoutWindowNotFound
+}
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoBlackoutDatabase struct {
+	client *mongo.Client
+}
+
+func NewMongoBlackoutDatabase(uri string) (*MongoBlackoutDatabase, error) {
+
+	database := new(MongoBlackoutDatabase)
+
+	var err error
+	database.client, err = mongo.Connect(context.TODO(), options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, err
+	}
+
+	return database, nil
+}
+
+func (database *MongoBlackoutDatabase) GetAll() (blackouts []interfaces.Blackout, err error) {
+
+	d := database.client.Database("cluster-tools")
+	c := d.Collection("blackouts")
+
+	cursor, err := c.Find(context.TODO(), bson.D{})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cursor.All(context.TODO(), &blackouts); err != nil {
+		return nil, err
+	}
+
+	return blackouts, nil
+}
+
+func (database *MongoBlackoutDatabase) Create(blackout *interfaces.Blackout) error {
+
+	if err := blackout.Validate(); err != nil {
+		return err
+	}
+
+	d := database.client.Database("cluster-tools")
+	c := d.Collection("blackouts")
+
+	mongoFilter := bson.D{{"name", bson.D{{"$eq", blackout.Name}}}}
+	if count, err := c.CountDocuments(context.TODO(), mongoFilter); err != nil {
+		return err
+	} else if count > 0 {
+		return errors.New("blackout window with the same name already exists")
+	}
+
+	_, err := c.InsertOne(context.TODO(), blackout)
+	return err
+}
+
+func (database *MongoBlackoutDatabase) Delete(name string) error {
+
+	d := database.client.Database("cluster-tools")
+	c := d.Collection("blackouts")
+
+	mongoFilter := bson.D{{"name", bson.D{{"$eq", name}}}}
+
+	result, err := c.DeleteOne(context.TODO(), mongoFilter)
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return interfaces.BlackoutWindowNotFound
+	}
+
+	return nil
+}
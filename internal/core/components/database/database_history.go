@@ -0,0 +1,33 @@
+package database
+
+import (
+	"time"
+)
+
+const (
+	RunActive  = "active"  // the supervisor provisioned for the run has not finished
+	RunFailed  = "failed"  // a supervisor could not be provisioned for the run
+	RunSkipped = "skipped" // the run was dropped by a blackout window or the concurrency policy
+)
+
+// Run
+// A single run of a scheduled job, linking the time it was triggered to the
+// supervisor that was provisioned for it and how that supervisor finished.
+type Run struct {
+	Job        string        `json:"job" bson:"job"`
+	Supervisor uint64        `json:"supervisor,omitempty" bson:"supervisor,omitempty"`
+	Scheduled  time.Time     `json:"scheduled" bson:"scheduled"`
+	Started    time.Time     `json:"started" bson:"started"`
+	Attempt    int           `json:"attempt,omitempty" bson:"attempt,omitempty"`
+	Finished   time.Time     `json:"finished,omitempty" bson:"finished,omitempty"`
+	Status     string        `json:"status" bson:"status"`
+	Duration   time.Duration `json:"duration,omitempty" bson:"duration,omitempty"`
+	Error      string        `json:"error,omitempty" bson:"error,omitempty"`
+	Blackout   string        `json:"blackout,omitempty" bson:"blackout,omitempty"` // the blackout window that skipped or deferred the run
+}
+
+type HistoryDatabase interface {
+	Get(identifier string) (runs []Run, err error)
+	Create(run Run) (err error)
+	Finish(identifier string, supervisor uint64, status string, at time.Time) (err error)
+}
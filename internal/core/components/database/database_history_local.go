@@ -0,0 +1,142 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+type LocalHistoryDatabase struct {
+	runs  map[string][]Run
+	mutex sync.RWMutex
+}
+
+func NewLocalHistoryDatabase() *LocalHistoryDatabase {
+
+	db := new(LocalHistoryDatabase)
+	db.runs = make(map[string][]Run)
+
+	return db
+}
+
+// Load
+// Reads the run history of each job from the json files in the folder.
+func (db *LocalHistoryDatabase) Load(path string) error {
+
+	if fInfo, err := os.Stat(path); os.IsNotExist(err) || (os.IsExist(err) && !fInfo.IsDir()) {
+		return fmt.Errorf("%s is not a valid directory on the system", path)
+	}
+
+	files, err := filepath.Glob(filepath.Join(path, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	for _, file := range files {
+
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		runs := make([]Run, 0)
+		if err = json.Unmarshal(b, &runs); err != nil {
+			return fmt.Errorf("failed to read run history %s: %w", file, err)
+		}
+
+		identifier := strings.TrimSuffix(filepath.Base(file), ".json")
+		db.runs[identifier] = runs
+	}
+
+	return nil
+}
+
+// Save
+// Writes the run history of each job into its own json file in the folder.
+func (db *LocalHistoryDatabase) Save(path string) error {
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return err
+	}
+
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	for identifier, runs := range db.runs {
+
+		b, err := json.Marshal(runs)
+		if err != nil {
+			return err
+		}
+
+		// the cli reads the files while the core runs, so they are replaced atomically
+		if err = writeFile(filepath.Join(path, identifier+".json"), b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *LocalHistoryDatabase) Get(identifier string) (runs []Run, err error) {
+
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	records, found := db.runs[identifier]
+	if !found {
+		return make([]Run, 0), nil
+	}
+
+	runs = make([]Run, len(records))
+	copy(runs, records)
+
+	return runs, nil
+}
+
+func (db *LocalHistoryDatabase) Create(run Run) (err error) {
+
+	if run.Job == "" {
+		return errors.New("run is missing a job identifier")
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	db.runs[run.Job] = append(db.runs[run.Job], run)
+
+	return nil
+}
+
+func (db *LocalHistoryDatabase) Finish(identifier string, supervisor uint64, status string, at time.Time) (err error) {
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	runs := db.runs[identifier]
+	for idx := range runs {
+		if runs[idx].Supervisor == supervisor {
+			runs[idx].Status = status
+			runs[idx].Finished = at
+			runs[idx].Duration = at.Sub(runs[idx].Started)
+			return nil
+		}
+	}
+
+	return errors.New("run does not exist")
+}
+
+func (db *LocalHistoryDatabase) Print() {
+
+	for identifier, runs := range db.runs {
+		fmt.Printf("├─ %s (num of runs: %d)\n", identifier, len(runs))
+	}
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type MongoHistoryDatabase struct {
+	client *mongo.Client
+}
+
+func NewMongoHistoryDatabase(uri string) (*MongoHistoryDatabase, error) {
+
+	database := new(MongoHistoryDatabase)
+
+	var err error
+	database.client, err = mongo.Connect(context.TODO(), options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, err
+	}
+
+	return database, nil
+}
+
+func (database *MongoHistoryDatabase) Get(identifier string) (runs []Run, err error) {
+
+	d := database.client.Database("cluster-tools")
+	c := d.Collection("history")
+
+	mongoFilter := bson.D{{"job", bson.D{{"$eq", identifier}}}}
+
+	cursor, err := c.Find(context.TODO(), mongoFilter, options.Find().SetSort(bson.D{{"started", 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cursor.All(context.TODO(), &runs); err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+func (database *MongoHistoryDatabase) Create(run Run) (err error) {
+
+	if run.Job == "" {
+		return errors.New("run is missing a job identifier")
+	}
+
+	d := database.client.Database("cluster-tools")
+	c := d.Collection("history")
+
+	_, err = c.InsertOne(context.TODO(), run)
+	return err
+}
+
+func (database *MongoHistoryDatabase) Finish(identifier string, supervisor uint64, status string, at time.Time) (err error) {
+
+	d := database.client.Database("cluster-tools")
+	c := d.Collection("history")
+
+	mongoFilter := bson.D{
+		{"$and",
+			bson.A{
+				bson.D{{"job", bson.D{{"$eq", identifier}}}},
+				bson.D{{"supervisor", bson.D{{"$eq", supervisor}}}},
+			},
+		},
+	}
+
+	run := Run{}
+	if err = c.FindOne(context.TODO(), mongoFilter).Decode(&run); err != nil {
+		return err
+	}
+
+	update := bson.D{{"$set", bson.D{
+		{"status", status},
+		{"finished", at},
+		{"duration", at.Sub(run.Started)},
+	}}}
+
+	_, err = c.UpdateOne(context.TODO(), mongoFilter, update)
+	return err
+}
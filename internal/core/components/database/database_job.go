@@ -1,10 +1,16 @@
 package database
 
-import "github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+import (
+	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+	"time"
+)
 
 type JobDatabase interface {
 	GetAll() ([]interfaces.Job, error)
 	GetBy(filter *interfaces.Filter) ([]interfaces.Job, error)
 	Create(job *interfaces.Job) error
 	Delete(filter *interfaces.Filter) error
+	MarkFired(identifier string, at time.Time) error
+	SetPaused(identifier string, paused bool) error
+	MarkFinished(identifier string, result string) error
 }
@@ -8,17 +8,23 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+	"time"
 )
 
 type LocalJobDatabase struct {
-	jobs  []interfaces.Job
-	mutex sync.RWMutex
+	jobs    []interfaces.Job
+	files   map[string]string // the schedule file each job is saved to, by identifier
+	removed map[string]bool   // the jobs deleted since the schedule files were last saved
+	mutex   sync.RWMutex
 }
 
 func NewLocalJobDatabase() *LocalJobDatabase {
 	j := new(LocalJobDatabase)
 	j.jobs = make([]interfaces.Job, 0)
+	j.files = make(map[string]string)
+	j.removed = make(map[string]bool)
 	return j
 }
 
@@ -27,96 +33,203 @@ func NewLocalJobDatabase() *LocalJobDatabase {
 func (database *LocalJobDatabase) Initialize(dump *interfaces.Dump) error {
 
 	for _, job := range dump.Jobs {
+		if err := job.Validate(); err != nil {
+			return fmt.Errorf("job %s could not be loaded: %w", job.Identifier, err)
+		}
 		database.jobs = append(database.jobs, job)
 	}
 
 	return nil
 }
 
-// Load
-// Loads a set of static jobs defined in a yaml file into runtime.
-func (database *LocalJobDatabase) Load(path string) error {
+// ScheduleFile
+// The jobs and blackout windows defined in a single file of the schedules folder.
+type ScheduleFile struct {
+	Path string
+	Dump *interfaces.Dump
+}
+
+// ReadSchedules
+// Parses every yaml file in the schedules folder in lexical order.
+func ReadSchedules(path string) ([]ScheduleFile, error) {
 
 	// if the path does not exist, or the path does and is not a directory, stop
 	// we are looking for a folder that has yaml files with jobs
 	if fInfo, err := os.Stat(path); os.IsNotExist(err) || (os.IsExist(err) && !fInfo.IsDir()) {
 		output := fmt.Sprintf("%s is not a valid directory on the system", path)
-		return errors.New(output)
+		return nil, errors.New(output)
 	}
 
+	files := make([]ScheduleFile, 0)
+
 	err := filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
 
-		// we don't care to open files that are directories
-		if d.IsDir() {
+		if err != nil {
+			return err
+		}
+
+		// we don't care to open files that are directories, hidden files
+		// are the temporary files of a save that has not finished
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".") {
 			return nil
 		}
 
-		b, err := os.ReadFile(path)
+		dump, err := readScheduleFile(path)
 		if err != nil {
 			return err
 		}
 
-		dump := &interfaces.Dump{}
-		if err = yaml.Unmarshal(b, dump); err != nil {
+		files = append(files, ScheduleFile{Path: path, Dump: dump})
+		return nil
+	})
+
+	// if filepath.walkdir returns an error, it will be passed here
+	return files, err
+}
+
+// Load
+// Loads a set of static jobs defined in a yaml file into runtime.
+func (database *LocalJobDatabase) Load(path string) error {
+
+	files, err := ReadSchedules(path)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err = database.Initialize(file.Dump); err != nil {
 			return err
 		}
+		// jobs are saved back into the file they were defined in
+		for _, job := range file.Dump.Jobs {
+			database.files[job.Identifier] = file.Path
+		}
+	}
+
+	return nil
+}
+
+// readScheduleFile
+// Parses the jobs and blackout windows defined in a schedule file.
+func readScheduleFile(path string) (*interfaces.Dump, error) {
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dump := &interfaces.Dump{}
+	if err = yaml.Unmarshal(b, dump); err != nil {
+		return nil, fmt.Errorf("%s could not be parsed: %w", path, err)
+	}
 
-		if err = database.Initialize(dump); err != nil {
+	return dump, nil
+}
+
+// WriteScheduleFile
+// Applies the update to the contents of a schedule file and atomically replaces it, the
+// dump is written to a temporary file in the same folder that is renamed over the old
+// file so the core and cli never read a partial file. A file left without any jobs or
+// blackout windows is removed.
+func WriteScheduleFile(path string, update func(dump *interfaces.Dump)) error {
+
+	dump := &interfaces.Dump{}
+	if _, err := os.Stat(path); err == nil {
+		if dump, err = readScheduleFile(path); err != nil {
 			return err
 		}
+	}
+
+	update(dump)
 
+	if (len(dump.Jobs) == 0) && (len(dump.Blackouts) == 0) {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
 		return nil
-	})
+	}
 
-	// if filepath.walkdir returns an error, it will be passed here
-	return err
+	b, err := yaml.Marshal(dump)
+	if err != nil {
+		return fmt.Errorf("failed to turn jobs into dump file %s", err.Error())
+	}
+
+	if err = writeFile(path, b); err != nil {
+		return fmt.Errorf("failed to write dump to file %s", err.Error())
+	}
+
+	return nil
 }
 
 // Save
-// Move all jobs in the scheduler into files seperated by their modules.
+// Writes every job back into the schedule file it was loaded from, jobs that were created
+// at runtime are saved into the file of their module (<module>.yml). Jobs the database does
+// not know of, ex. added by the cli while the core is running, are kept in their files.
 func (database *LocalJobDatabase) Save(path string) error {
 
-	if fInfo, err := os.Stat(path); os.IsNotExist(err) || (os.IsExist(err) && !fInfo.IsDir()) {
-		output := fmt.Sprintf("%s is not a valid path to a directory", path)
-		return errors.New(output)
+	if err := os.MkdirAll(path, 0750); err != nil {
+		return fmt.Errorf("%s is not a valid path to a directory", path)
 	}
 
-	// clear all the files that already existed in the folder, they should have been loaded
-	// into the scheduler if the Load function was called correctly
-	os.RemoveAll(path)
-	os.MkdirAll(path, 0750)
+	database.mutex.Lock()
+	defer database.mutex.Unlock()
 
-	moduleSeperatedJobs := make(map[string][]interfaces.Job)
+	known := make(map[string]bool)
+	grouped := make(map[string][]interfaces.Job)
 
-	// order all the jobs by the module they belong to
+	// files that lost all their jobs still need to be rewritten
+	for _, file := range database.files {
+		grouped[file] = make([]interfaces.Job, 0)
+	}
+
+	// order all the jobs by the file they belong to
 	for _, job := range database.jobs {
-		if _, found := moduleSeperatedJobs[job.Module]; !found {
-			moduleSeperatedJobs[job.Module] = make([]interfaces.Job, 0)
+		file, found := database.files[job.Identifier]
+		if !found {
+			file = filepath.Join(path, job.Module+".yml")
+			database.files[job.Identifier] = file
 		}
-		moduleSeperatedJobs[job.Module] = append(moduleSeperatedJobs[job.Module], job)
+		grouped[file] = append(grouped[file], job)
+		known[job.Identifier] = true
 	}
 
-	// save each module's job into its own file
-	for module, jobs := range moduleSeperatedJobs {
+	for file, jobs := range grouped {
+
+		err := WriteScheduleFile(file, func(dump *interfaces.Dump) {
 
-		filePath := fmt.Sprintf("%s/schedule_%s.yml", path, module)
-		dump := &interfaces.Dump{Jobs: jobs}
-		b, err := yaml.Marshal(dump)
+			merged := make([]interfaces.Job, 0, len(dump.Jobs)+len(jobs))
+			for _, job := range dump.Jobs {
+				if !known[job.Identifier] && !database.removed[job.Identifier] {
+					merged = append(merged, job)
+				}
+			}
+			dump.Jobs = append(merged, jobs...)
+		})
 		if err != nil {
-			output := fmt.Sprintf("failed to turn jobs into dump file %s", err.Error())
-			return errors.New(output)
+			return err
 		}
-		if err = os.WriteFile(filePath, b, 0750); err != nil {
-			output := fmt.Sprintf("failed to write dump to file %s", err.Error())
-			return errors.New(output)
+	}
+
+	// the deleted jobs are no longer in any file
+	for identifier := range database.removed {
+		if !known[identifier] {
+			delete(database.files, identifier)
 		}
 	}
+	database.removed = make(map[string]bool)
 
 	return nil
 }
 
 func (database *LocalJobDatabase) GetAll() ([]interfaces.Job, error) {
-	return database.jobs, nil
+
+	database.mutex.RLock()
+	defer database.mutex.RUnlock()
+
+	jobs := make([]interfaces.Job, len(database.jobs))
+	copy(jobs, database.jobs)
+
+	return jobs, nil
 }
 
 func (database *LocalJobDatabase) GetBy(filter *interfaces.Filter) ([]interfaces.Job, error) {
@@ -133,6 +246,7 @@ func (database *LocalJobDatabase) GetBy(filter *interfaces.Filter) ([]interfaces
 	useModule := filter.UseModule()
 	useCluster := filter.UseCluster()
 	useInterval := filter.UseInterval()
+	useSelector := filter.UseSelector()
 
 	for _, job := range database.jobs {
 
@@ -140,12 +254,17 @@ func (database *LocalJobDatabase) GetBy(filter *interfaces.Filter) ([]interfaces
 		clusterMatch := job.Cluster == filter.Cluster
 		intervalMatch := job.Interval.Equals(&filter.Interval)
 
+		if !filter.Selector.Matches(job.Labels) {
+			continue
+		}
+
 		if useId && (job.Identifier == filter.Identifier) {
 			jobs = append(jobs, job)
 			break
 		} else if (useModule && moduleMatch) ||
 			(useCluster && moduleMatch && clusterMatch) ||
-			(useInterval && moduleMatch && clusterMatch && intervalMatch) {
+			(useInterval && moduleMatch && clusterMatch && intervalMatch) ||
+			useSelector {
 			jobs = append(jobs, job)
 		}
 	}
@@ -155,6 +274,10 @@ func (database *LocalJobDatabase) GetBy(filter *interfaces.Filter) ([]interfaces
 
 func (database *LocalJobDatabase) Create(job *interfaces.Job) error {
 
+	if err := job.Validate(); err != nil {
+		return err
+	}
+
 	// only create a read lock for the duration we are validating
 	// no other equivalent job exists within the scheduler as to
 	// no interrupt parallel read tasks
@@ -199,23 +322,91 @@ func (database *LocalJobDatabase) Delete(filter *interfaces.Filter) error {
 	useCluster := filter.UseCluster()
 	useInterval := filter.UseInterval()
 
-	for idx, jobInstance := range database.jobs {
+	// the jobs that do not match are copied over, removing elements while
+	// ranging over the same slice would skip the element after each removal
+	kept := make([]interfaces.Job, 0, len(database.jobs))
+	found := false
+
+	for _, jobInstance := range database.jobs {
 
 		moduleSame := jobInstance.Module == filter.Module
 		clusterSame := jobInstance.Cluster == filter.Cluster
 		intervalSame := jobInstance.Interval.Equals(&filter.Interval)
 
-		if useId && (jobInstance.Identifier == filter.Identifier) {
-			database.jobs = append(database.jobs[:idx], database.jobs[idx+1:]...)
-			break
-		} else if (useModule && moduleSame) || (useCluster && moduleSame && clusterSame) || (useInterval && moduleSame && clusterSame && intervalSame) {
-			database.jobs = append(database.jobs[:idx], database.jobs[idx+1:]...)
+		// nothing is removed after the job with the identifier was found
+		matches := false
+		if !found {
+			if useId && (jobInstance.Identifier == filter.Identifier) {
+				matches, found = true, true
+			} else if (useModule && moduleSame) || (useCluster && moduleSame && clusterSame) || (useInterval && moduleSame && clusterSame && intervalSame) {
+				matches = true
+			}
+		}
+
+		if matches {
+			database.removed[jobInstance.Identifier] = true
+		} else {
+			kept = append(kept, jobInstance)
 		}
 	}
+	database.jobs = kept
 
 	return nil
 }
 
+// MarkFired
+// Records the last time the scheduler fired the job. The timestamp is persisted
+// alongside the job so missed runs can be detected after a restart.
+func (database *LocalJobDatabase) MarkFired(identifier string, at time.Time) error {
+
+	database.mutex.Lock()
+	defer database.mutex.Unlock()
+
+	for idx := range database.jobs {
+		if database.jobs[idx].Identifier == identifier {
+			database.jobs[idx].LastFired = at
+			return nil
+		}
+	}
+
+	return errors.New("job does not exist")
+}
+
+// SetPaused
+// Pauses or resumes the job, a paused job is kept but never scheduled.
+func (database *LocalJobDatabase) SetPaused(identifier string, paused bool) error {
+
+	database.mutex.Lock()
+	defer database.mutex.Unlock()
+
+	for idx := range database.jobs {
+		if database.jobs[idx].Identifier == identifier {
+			database.jobs[idx].Paused = paused
+			return nil
+		}
+	}
+
+	return errors.New("job does not exist")
+}
+
+// MarkFinished
+// Records the result of a run-at job, a finished job is never scheduled again.
+func (database *LocalJobDatabase) MarkFinished(identifier string, result string) error {
+
+	database.mutex.Lock()
+	defer database.mutex.Unlock()
+
+	for idx := range database.jobs {
+		if database.jobs[idx].Identifier == identifier {
+			database.jobs[idx].Finished = true
+			database.jobs[idx].Result = result
+			return nil
+		}
+	}
+
+	return errors.New("job does not exist")
+}
+
 func (database *LocalJobDatabase) Print() {
 
 	for _, job := range database.jobs {
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalJobDatabase_Save(t *testing.T) {
+
+	path := t.TempDir()
+
+	// a file written by hand with a job and a blackout window
+	err := WriteScheduleFile(filepath.Join(path, "nightly.yml"), func(dump *interfaces.Dump) {
+		dump.Jobs = []interfaces.Job{{Identifier: "nightly", Module: "common", Cluster: "vec", Schedule: "@daily"}}
+		dump.Blackouts = []interfaces.Blackout{{Name: "maintenance", Schedule: "0 1 * * sun", Duration: 60}}
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	db := NewLocalJobDatabase()
+	if err = db.Load(path); err != nil {
+		t.Error(err)
+		return
+	}
+
+	db.Create(&interfaces.Job{Identifier: "hourly", Module: "common", Cluster: "vec", Schedule: "@hourly"})
+	db.Delete(&interfaces.Filter{Identifier: "nightly"})
+
+	if err = db.Save(path); err != nil {
+		t.Error(err)
+		return
+	}
+
+	files, err := ReadSchedules(path)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(files) != 2 {
+		t.Error("expected the created job to be saved into the file of its module")
+		return
+	}
+
+	for _, file := range files {
+		switch filepath.Base(file.Path) {
+		case "common.yml":
+			if (len(file.Dump.Jobs) != 1) || (file.Dump.Jobs[0].Identifier != "hourly") {
+				t.Error("expected the module file to hold the created job")
+			}
+		case "nightly.yml":
+			if (len(file.Dump.Jobs) != 0) || (len(file.Dump.Blackouts) != 1) {
+				t.Error("expected the deleted job to be removed and the blackout window to be kept")
+			}
+		default:
+			t.Error("unexpected schedule file " + file.Path)
+		}
+	}
+
+	entries, _ := os.ReadDir(path)
+	if len(entries) != 2 {
+		t.Error("expected no temporary files to be left behind")
+	}
+}
+
+func TestLocalJobDatabase_GetBy(t *testing.T) {
+
+	db := NewLocalJobDatabase()
+	db.Create(&interfaces.Job{Identifier: "invoices", Module: "common", Cluster: "vec", Schedule: "@daily",
+		Labels: map[string]string{"team": "billing"}})
+	db.Create(&interfaces.Job{Identifier: "reports", Module: "common", Cluster: "vec", Schedule: "@hourly",
+		Labels: map[string]string{"team": "analytics"}})
+	db.Create(&interfaces.Job{Identifier: "cleanup", Module: "other", Cluster: "vec", Schedule: "@hourly"})
+
+	selector, _ := interfaces.ParseSelector("team=billing")
+
+	jobs, _ := db.GetBy(&interfaces.Filter{Selector: selector})
+	if (len(jobs) != 1) || (jobs[0].Identifier != "invoices") {
+		t.Error("expected a selector on its own to search every job")
+	}
+
+	selector, _ = interfaces.ParseSelector("team")
+
+	jobs, _ = db.GetBy(&interfaces.Filter{Module: "common", Selector: selector})
+	if len(jobs) != 2 {
+		t.Error("expected the selector to narrow the jobs of the module")
+	}
+
+	jobs, _ = db.GetBy(&interfaces.Filter{Module: "other", Selector: selector})
+	if len(jobs) != 0 {
+		t.Error("expected a job without the label to not match")
+	}
+
+	if err := db.Create(&interfaces.Job{Identifier: "bad", Module: "common", Cluster: "vec", Schedule: "@daily",
+		Labels: map[string]string{"team": "bill ing"}}); err == nil {
+		t.Error("expected a label that can not be selected to be rejected")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
 )
 
 type JobMongoDatabase struct {
@@ -66,6 +67,8 @@ func (database JobMongoDatabase) GetBy(filter *interfaces.Filter) (records []int
 		}
 	} else if filter.UseIdentifier() {
 		mongoFilter = bson.D{{"identifier", bson.D{{"$eq", filter.Identifier}}}}
+	} else if filter.UseSelector() {
+		mongoFilter = bson.D{}
 	}
 
 	cursor, err := c.Find(context.TODO(), mongoFilter)
@@ -77,7 +80,15 @@ func (database JobMongoDatabase) GetBy(filter *interfaces.Filter) (records []int
 		return nil, err
 	}
 
-	return records, nil
+	// labels are matched once the jobs are decoded
+	selected := make([]interfaces.Job, 0, len(records))
+	for _, job := range records {
+		if filter.Selector.Matches(job.Labels) {
+			selected = append(selected, job)
+		}
+	}
+
+	return selected, nil
 }
 
 func (database JobMongoDatabase) Create(job *interfaces.Job) (err error) {
@@ -86,6 +97,10 @@ func (database JobMongoDatabase) Create(job *interfaces.Job) (err error) {
 		return errors.New("job can not be nil")
 	}
 
+	if err = job.Validate(); err != nil {
+		return err
+	}
+
 	d := database.client.Database("cluster-tools")
 	c := d.Collection("jobs")
 
@@ -133,3 +148,63 @@ func (database JobMongoDatabase) Delete(filter *interfaces.Filter) (err error) {
 
 	return nil
 }
+
+func (database JobMongoDatabase) MarkFired(identifier string, at time.Time) (err error) {
+
+	d := database.client.Database("cluster-tools")
+	c := d.Collection("jobs")
+
+	mongoFilter := bson.D{{"identifier", bson.D{{"$eq", identifier}}}}
+	update := bson.D{{"$set", bson.D{{"last-fired", at}}}}
+
+	result, err := c.UpdateOne(context.TODO(), mongoFilter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount < 1 {
+		return errors.New("job does not exist")
+	}
+
+	return nil
+}
+
+func (database JobMongoDatabase) SetPaused(identifier string, paused bool) (err error) {
+
+	d := database.client.Database("cluster-tools")
+	c := d.Collection("jobs")
+
+	mongoFilter := bson.D{{"identifier", bson.D{{"$eq", identifier}}}}
+	update := bson.D{{"$set", bson.D{{"paused", paused}}}}
+
+	result, err := c.UpdateOne(context.TODO(), mongoFilter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount < 1 {
+		return errors.New("job does not exist")
+	}
+
+	return nil
+}
+
+func (database JobMongoDatabase) MarkFinished(identifier string, result string) (err error) {
+
+	d := database.client.Database("cluster-tools")
+	c := d.Collection("jobs")
+
+	mongoFilter := bson.D{{"identifier", bson.D{{"$eq", identifier}}}}
+	update := bson.D{{"$set", bson.D{{"finished", true}, {"result", result}}}}
+
+	updateResult, err := c.UpdateOne(context.TODO(), mongoFilter, update)
+	if err != nil {
+		return err
+	}
+
+	if updateResult.MatchedCount < 1 {
+		return errors.New("job does not exist")
+	}
+
+	return nil
+}
@@ -9,6 +9,7 @@ type Statistic struct {
 	Timestamp time.Time             `json:"timestamp"`
 	Elapsed   time.Duration         `json:"elapsed"`
 	Stats     interfaces.Statistics `json:"statistics"`
+	Labels    map[string]string     `json:"labels,omitempty"` // of the supervisor the statistics were recorded for
 }
 
 type StatisticDatabase interface {
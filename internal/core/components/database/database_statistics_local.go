@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
 	"os"
 	"sync"
 	"time"
@@ -50,9 +51,10 @@ func (db *LocalStatisticDatabase) Save(path string) error {
 }
 
 type StatisticFilter struct {
-	Module  string
-	Cluster string
-	Verbose bool
+	Module   string
+	Cluster  string
+	Verbose  bool
+	Selector interfaces.Selector // the labels of the records must match
 }
 
 func (db *LocalStatisticDatabase) Get(filter StatisticFilter) (records []Statistic, err error) {
@@ -86,7 +88,21 @@ func (db *LocalStatisticDatabase) Get(filter StatisticFilter) (records []Statist
 		return records, err
 	}
 
-	return records, err
+	return filter.Select(records), err
+}
+
+// Select
+// Returns the records with labels that match the selector of the filter.
+func (filter StatisticFilter) Select(records []Statistic) []Statistic {
+
+	selected := make([]Statistic, 0, len(records))
+	for _, record := range records {
+		if filter.Selector.Matches(record.Labels) {
+			selected = append(selected, record)
+		}
+	}
+
+	return selected
 }
 
 func (db *LocalStatisticDatabase) Create(moduleId, clusterId string, statistic Statistic) (err error) {
@@ -52,7 +52,7 @@ func (database *MongoStatisticsDatabase) Get(filter StatisticFilter) (records []
 		return nil, err
 	}
 
-	return records, nil
+	return filter.Select(records), nil
 }
 
 func (database *MongoStatisticsDatabase) Create(moduleId, clusterId string, statistic Statistic) (err error) {
@@ -0,0 +1,17 @@
+package database
+
+import (
+	"github.com/GabeCordo/cluster-tools/internal/core/components/supervisor"
+)
+
+// SupervisorDatabase
+// Stores the supervisor registry so supervisors, and the identifiers given to them,
+// survive the core restarting. Archived supervisors are kept out of GetAll so they
+// are not restored into the registry.
+type SupervisorDatabase interface {
+	GetAll() (supervisors []*supervisor.Supervisor, err error)
+	Store(instance *supervisor.Supervisor) (err error)
+	Delete(identifier uint64) (err error)
+	Archive(identifier uint64) (err error)
+	Next() (identifier uint64, err error)
+}
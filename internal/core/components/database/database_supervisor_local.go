@@ -0,0 +1,256 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/GabeCordo/cluster-tools/internal/core/components/supervisor"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	SupervisorCounterFile   = "counter.json"
+	SupervisorArchiveFolder = "archive"
+)
+
+type LocalSupervisorDatabase struct {
+	supervisors map[uint64]*supervisor.Supervisor
+	next        uint64
+
+	dirty   map[uint64]bool // supervisors stored since the last save
+	removed map[uint64]bool // supervisors deleted since the last save
+	mutex   sync.RWMutex
+
+	// supervisors archived since the last save, they are only held until they are written
+	archived map[uint64]*supervisor.Supervisor
+}
+
+func NewLocalSupervisorDatabase() *LocalSupervisorDatabase {
+
+	db := new(LocalSupervisorDatabase)
+	db.supervisors = make(map[uint64]*supervisor.Supervisor)
+	db.dirty = make(map[uint64]bool)
+	db.removed = make(map[uint64]bool)
+	db.archived = make(map[uint64]*supervisor.Supervisor)
+	db.next = 1
+
+	return db
+}
+
+// Load
+// Reads every supervisor from its own json file in the folder, and the identifier
+// the next supervisor is given from the counter file.
+func (db *LocalSupervisorDatabase) Load(path string) error {
+
+	if fInfo, err := os.Stat(path); os.IsNotExist(err) || (os.IsExist(err) && !fInfo.IsDir()) {
+		return fmt.Errorf("%s is not a valid directory on the system", path)
+	}
+
+	files, err := filepath.Glob(filepath.Join(path, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	for _, file := range files {
+
+		// temporary files left behind by a save that was interrupted
+		if strings.HasPrefix(filepath.Base(file), ".") {
+			continue
+		}
+
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		if filepath.Base(file) == SupervisorCounterFile {
+			if err = json.Unmarshal(b, &db.next); err != nil {
+				return fmt.Errorf("failed to read supervisor counter %s: %w", file, err)
+			}
+			continue
+		}
+
+		// anything other than a supervisor is ignored
+		if _, err = strconv.ParseUint(strings.TrimSuffix(filepath.Base(file), ".json"), 10, 64); err != nil {
+			continue
+		}
+
+		instance := new(supervisor.Supervisor)
+		if err = json.Unmarshal(b, instance); err != nil {
+			return fmt.Errorf("failed to read supervisor %s: %w", file, err)
+		}
+		db.supervisors[instance.Id] = instance
+
+		if instance.Id >= db.next {
+			db.next = instance.Id + 1
+		}
+	}
+
+	return nil
+}
+
+// Save
+// Writes the supervisors stored since the last save into their own json files in
+// the folder, along with the identifier the next supervisor is given. Archived
+// supervisors are moved into the archive folder, which is never loaded.
+func (db *LocalSupervisorDatabase) Save(path string) error {
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return err
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	for id := range db.dirty {
+
+		b, err := json.Marshal(db.supervisors[id])
+		if err != nil {
+			return err
+		}
+
+		if err = writeFile(filepath.Join(path, fmt.Sprintf("%d.json", id)), b); err != nil {
+			return err
+		}
+		delete(db.dirty, id)
+	}
+
+	if len(db.archived) > 0 {
+		if err := os.MkdirAll(filepath.Join(path, SupervisorArchiveFolder), 0750); err != nil {
+			return err
+		}
+	}
+
+	for id, instance := range db.archived {
+
+		b, err := json.Marshal(instance)
+		if err != nil {
+			return err
+		}
+
+		if err = writeFile(filepath.Join(path, SupervisorArchiveFolder, fmt.Sprintf("%d.json", id)), b); err != nil {
+			return err
+		}
+		delete(db.archived, id)
+		db.removed[id] = true
+	}
+
+	for id := range db.removed {
+
+		err := os.Remove(filepath.Join(path, fmt.Sprintf("%d.json", id)))
+		if (err != nil) && !os.IsNotExist(err) {
+			return err
+		}
+		delete(db.removed, id)
+	}
+
+	b, err := json.Marshal(db.next)
+	if err != nil {
+		return err
+	}
+
+	return writeFile(filepath.Join(path, SupervisorCounterFile), b)
+}
+
+func (db *LocalSupervisorDatabase) GetAll() (supervisors []*supervisor.Supervisor, err error) {
+
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	supervisors = make([]*supervisor.Supervisor, 0, len(db.supervisors))
+	for _, instance := range db.supervisors {
+		supervisors = append(supervisors, instance.Snapshot())
+	}
+
+	sort.Slice(supervisors, func(i, j int) bool {
+		return supervisors[i].Id < supervisors[j].Id
+	})
+
+	return supervisors, nil
+}
+
+func (db *LocalSupervisorDatabase) Store(instance *supervisor.Supervisor) (err error) {
+
+	if (instance == nil) || (instance.Id == 0) {
+		return errors.New("supervisor is missing an identifier")
+	}
+
+	snapshot := instance.Snapshot()
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	db.supervisors[snapshot.Id] = snapshot
+	db.dirty[snapshot.Id] = true
+	delete(db.removed, snapshot.Id)
+	delete(db.archived, snapshot.Id)
+
+	if snapshot.Id >= db.next {
+		db.next = snapshot.Id + 1
+	}
+
+	return nil
+}
+
+func (db *LocalSupervisorDatabase) Delete(identifier uint64) (err error) {
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	if _, found := db.supervisors[identifier]; !found {
+		return supervisor.DoesNotExist
+	}
+
+	delete(db.supervisors, identifier)
+	delete(db.dirty, identifier)
+	db.removed[identifier] = true
+
+	return nil
+}
+
+// Archive
+// Drops the supervisor from memory once the next save moves it into the archive folder.
+func (db *LocalSupervisorDatabase) Archive(identifier uint64) (err error) {
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	instance, found := db.supervisors[identifier]
+	if !found {
+		return supervisor.DoesNotExist
+	}
+
+	delete(db.supervisors, identifier)
+	delete(db.dirty, identifier)
+	db.archived[identifier] = instance
+
+	return nil
+}
+
+func (db *LocalSupervisorDatabase) Next() (identifier uint64, err error) {
+
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	return db.next, nil
+}
+
+func (db *LocalSupervisorDatabase) Print() {
+
+	counts := make(map[supervisor.Status]int)
+	for _, instance := range db.supervisors {
+		counts[instance.Status]++
+	}
+
+	for status, count := range counts {
+		fmt.Printf("├─ %s (num of supervisors: %d)\n", status, count)
+	}
+}
@@ -0,0 +1,145 @@
+package database
+
+import (
+	"github.com/GabeCordo/cluster-tools/internal/core/components/supervisor"
+	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalSupervisorDatabase_Save(t *testing.T) {
+
+	path := t.TempDir()
+
+	registry := supervisor.NewRegistry()
+	for i := 0; i < 3; i++ {
+		registry.Create("127.0.0.1:8137", "common", "vec", &interfaces.Config{Identifier: "vec"})
+	}
+
+	db := NewLocalSupervisorDatabase()
+	for _, instance := range registry.GetBy(&supervisor.Filter{}) {
+		if id := instance.Id; id != 2 {
+			instance.Event(supervisor.Start)
+		}
+		if err := db.Store(instance); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	if err := db.Save(path); err != nil {
+		t.Error(err)
+		return
+	}
+
+	restored := NewLocalSupervisorDatabase()
+	if err := restored.Load(path); err != nil {
+		t.Error(err)
+		return
+	}
+
+	supervisors, _ := restored.GetAll()
+	if len(supervisors) != 3 {
+		t.Errorf("expected 3 supervisors to be restored but got %d", len(supervisors))
+		return
+	}
+
+	if (supervisors[1].Id != 2) || (supervisors[1].GetStatus() != supervisor.Created) {
+		t.Error("expected the supervisors to be restored in order with their status")
+	}
+
+	if len(supervisors[0].GetHistory()) != 2 {
+		t.Error("expected the transition history to be restored")
+	}
+
+	if next, _ := restored.Next(); next != 4 {
+		t.Errorf("expected the next identifier to be 4 but got %d", next)
+	}
+}
+
+func TestLocalSupervisorDatabase_Load(t *testing.T) {
+
+	path := t.TempDir()
+
+	db := NewLocalSupervisorDatabase()
+	registry := supervisor.NewRegistry()
+	instance, _ := registry.Get(registry.Create("127.0.0.1:8137", "common", "vec", &interfaces.Config{Identifier: "vec"}))
+	db.Store(instance)
+
+	if err := db.Save(path); err != nil {
+		t.Error(err)
+		return
+	}
+
+	// a save that was interrupted leaves a partly written temporary file behind
+	if err := os.WriteFile(filepath.Join(path, ".2.json"), []byte(`{"id": 2, "sta`), 0750); err != nil {
+		t.Error(err)
+		return
+	}
+
+	restored := NewLocalSupervisorDatabase()
+	if err := restored.Load(path); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if supervisors, _ := restored.GetAll(); len(supervisors) != 1 {
+		t.Error("expected the temporary file to be ignored")
+	}
+
+	entries, _ := os.ReadDir(path)
+	if len(entries) != 3 {
+		t.Error("expected the save to leave no temporary files of its own behind")
+	}
+}
+
+func TestLocalSupervisorDatabase_Archive(t *testing.T) {
+
+	path := t.TempDir()
+
+	db := NewLocalSupervisorDatabase()
+	registry := supervisor.NewRegistry()
+	for i := 0; i < 2; i++ {
+		instance, _ := registry.Get(registry.Create("127.0.0.1:8137", "common", "vec", &interfaces.Config{Identifier: "vec"}))
+		db.Store(instance)
+	}
+
+	if err := db.Save(path); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if err := db.Archive(1); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if err := db.Save(path); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(db.supervisors) != 1 || len(db.archived) != 0 {
+		t.Error("expected the archived supervisor to be dropped from memory once it is saved")
+	}
+
+	if _, err := os.Stat(filepath.Join(path, SupervisorArchiveFolder, "1.json")); err != nil {
+		t.Error("expected the archived supervisor to be written to the archive folder")
+	}
+
+	restored := NewLocalSupervisorDatabase()
+	if err := restored.Load(path); err != nil {
+		t.Error(err)
+		return
+	}
+
+	supervisors, _ := restored.GetAll()
+	if (len(supervisors) != 1) || (supervisors[0].Id != 2) {
+		t.Error("expected the archived supervisor to not be restored")
+	}
+
+	if next, _ := restored.Next(); next != 3 {
+		t.Error("expected the identifier of the archived supervisor to not be reused")
+	}
+}
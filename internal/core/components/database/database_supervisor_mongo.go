@@ -0,0 +1,119 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"github.com/GabeCordo/cluster-tools/internal/core/components/supervisor"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoSupervisorDatabase struct {
+	client *mongo.Client
+}
+
+func NewMongoSupervisorDatabase(uri string) (*MongoSupervisorDatabase, error) {
+
+	database := new(MongoSupervisorDatabase)
+
+	var err error
+	database.client, err = mongo.Connect(context.TODO(), options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, err
+	}
+
+	return database, nil
+}
+
+func (database *MongoSupervisorDatabase) GetAll() (supervisors []*supervisor.Supervisor, err error) {
+
+	d := database.client.Database("cluster-tools")
+	c := d.Collection("supervisors")
+
+	mongoFilter := bson.D{{"archived", bson.D{{"$ne", true}}}}
+
+	cursor, err := c.Find(context.TODO(), mongoFilter, options.Find().SetSort(bson.D{{"id", 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cursor.All(context.TODO(), &supervisors); err != nil {
+		return nil, err
+	}
+
+	return supervisors, nil
+}
+
+func (database *MongoSupervisorDatabase) Store(instance *supervisor.Supervisor) (err error) {
+
+	if (instance == nil) || (instance.Id == 0) {
+		return errors.New("supervisor is missing an identifier")
+	}
+
+	d := database.client.Database("cluster-tools")
+	c := d.Collection("supervisors")
+
+	mongoFilter := bson.D{{"id", bson.D{{"$eq", instance.Id}}}}
+
+	_, err = c.ReplaceOne(context.TODO(), mongoFilter, instance.Snapshot(), options.Replace().SetUpsert(true))
+	return err
+}
+
+func (database *MongoSupervisorDatabase) Delete(identifier uint64) (err error) {
+
+	d := database.client.Database("cluster-tools")
+	c := d.Collection("supervisors")
+
+	mongoFilter := bson.D{{"id", bson.D{{"$eq", identifier}}}}
+
+	result, err := c.DeleteOne(context.TODO(), mongoFilter)
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return supervisor.DoesNotExist
+	}
+
+	return nil
+}
+
+// Archive
+// Flags the supervisor so it is no longer restored, it is kept in the collection so the
+// identifiers given to supervisors are never reused.
+func (database *MongoSupervisorDatabase) Archive(identifier uint64) (err error) {
+
+	d := database.client.Database("cluster-tools")
+	c := d.Collection("supervisors")
+
+	mongoFilter := bson.D{{"id", bson.D{{"$eq", identifier}}}}
+	update := bson.D{{"$set", bson.D{{"archived", true}}}}
+
+	result, err := c.UpdateOne(context.TODO(), mongoFilter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return supervisor.DoesNotExist
+	}
+
+	return nil
+}
+
+func (database *MongoSupervisorDatabase) Next() (identifier uint64, err error) {
+
+	d := database.client.Database("cluster-tools")
+	c := d.Collection("supervisors")
+
+	latest := new(supervisor.Supervisor)
+	err = c.FindOne(context.TODO(), bson.D{}, options.FindOne().SetSort(bson.D{{"id", -1}})).Decode(latest)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 1, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	return latest.Id + 1, nil
+}
@@ -43,6 +43,10 @@ func (c *Cluster) SelectProcessor() *Processor {
 	// maybe consider something with the delays the current processors have
 	// or number of processes running
 
+	if len(c.processors) == 0 {
+		return nil
+	}
+
 	instance := c.processors[c.processorIndex]
 	if d := c.numOfProcessors - 1; d != 0 {
 		c.processorIndex = (c.processorIndex + 1) % (len(c.processors))
@@ -52,3 +56,30 @@ func (c *Cluster) SelectProcessor() *Processor {
 
 	return instance
 }
+
+// Candidates
+// Returns every processor of the cluster in the order they should be tried, starting with
+// the next processor in the rotation. The processor to avoid is tried last.
+func (c *Cluster) Candidates(avoid string) []*Processor {
+
+	first := c.SelectProcessor()
+	if first == nil {
+		return nil
+	}
+
+	candidates := []*Processor{first}
+	for _, instance := range c.processors {
+		if instance != first {
+			candidates = append(candidates, instance)
+		}
+	}
+
+	for idx, instance := range candidates {
+		if (instance.ToString() == avoid) && (idx != len(candidates)-1) {
+			candidates = append(append(candidates[:idx:idx], candidates[idx+1:]...), instance)
+			break
+		}
+	}
+
+	return candidates
+}
@@ -44,3 +44,32 @@ func TestCluster_SelectProcessor(t *testing.T) {
 	}
 
 }
+
+// TestCluster_Candidates
+// Test that every processor is a candidate, starting with the next one in the rotation
+// and with the processor to avoid tried last.
+func TestCluster_Candidates(t *testing.T) {
+
+	cluster := newCluster("test")
+
+	processor1 := newProcessor("localhost", 8000)
+	cluster.Add(processor1)
+	processor2 := newProcessor("localhost", 8001)
+	cluster.Add(processor2)
+	processor3 := newProcessor("localhost", 8002)
+	cluster.Add(processor3)
+
+	candidates := cluster.Candidates(processor1.ToString())
+	if (len(candidates) != 3) || (candidates[0] != processor2) || (candidates[2] != processor1) {
+		t.Error("expected the processor to avoid to be the last candidate")
+	}
+
+	candidates = cluster.Candidates("")
+	if (len(candidates) != 3) || (candidates[0] != processor2) {
+		t.Error("expected the first candidate to be the next processor in the rotation")
+	}
+
+	if len(newCluster("empty").Candidates("")) != 0 {
+		t.Error("expected a cluster without processors to have no candidates")
+	}
+}
@@ -21,3 +21,5 @@ var ClusterNotMounted = errors.New("cluster is not mounted")
 var CanNotProvisionStreamCluster = errors.New("stream clusters cannot be called manually like batch processes")
 
 var ClusterDoesNotExist = errors.New("cluster does not exist in the module")
+
+var NoProcessorAvailable = errors.New("no processor is available to run the cluster")
@@ -17,6 +17,16 @@ func (module *Module) IsMounted() bool {
 	return module.data.Mounted
 }
 
+// MaxConcurrency
+// Returns how many supervisors of the module can run at once, 0 for no limit.
+func (module *Module) MaxConcurrency() int {
+
+	module.mutex.RLock()
+	defer module.mutex.RUnlock()
+
+	return module.data.MaxConcurrency
+}
+
 func (module *Module) Mount() {
 
 	module.mutex.Lock()
@@ -33,6 +33,7 @@ func (table *Table) AddProcessor(cfg *interfaces.ProcessorConfig) error {
 	}
 
 	processor := newProcessor(cfg.Host, cfg.Port)
+	processor.MaxConcurrency = cfg.MaxConcurrency
 	table.processors = append(table.processors, processor)
 	table.NumOfProcessors++
 
@@ -187,6 +188,9 @@ func (table *Table) AddModule(processorName string, config *interfaces.ModuleCon
 		clusterInstance.data.Mode = export.Config.Mode
 	}
 
+	// the limit of the latest registration is used in case the processors disagree
+	moduleInstance.data.MaxConcurrency = config.MaxConcurrency
+
 	// TODO : allow the user to specify whether they want modules to be mounted by default
 	// for now modules will be mounted by default to make docker deployments easier
 	moduleInstance.Mount()
@@ -22,6 +22,8 @@ type Processor struct {
 	LastUpdate time.Time
 	Modules    []string
 	Retries    uint32
+
+	MaxConcurrency int // supervisors the processor can run at once, 0 for no limit
 }
 
 func (processor Processor) ToString() string {
@@ -72,6 +74,8 @@ type ModuleData struct {
 	Version float64
 	Contact interfaces.ModuleContact
 	Mounted bool
+
+	MaxConcurrency int // supervisors of the module that can run at once, 0 for no limit
 }
 
 type Module struct {
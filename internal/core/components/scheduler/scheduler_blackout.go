@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	"fmt"
+	"github.com/GabeCordo/cluster-tools/internal/core/components/database"
+	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+	"log"
+	"time"
+)
+
+// Blackout
+// Returns the blackout window covering the job at the time t and when it closes,
+// when windows overlap the one that closes last is returned. Returns nil if the
+// job is free to run.
+func (scheduler *Scheduler) Blackout(job *interfaces.Job, t time.Time) (window *interfaces.Blackout, end time.Time) {
+
+	blackouts, err := scheduler.Blackouts.GetAll()
+	if err != nil {
+		log.Println(err)
+		return nil, end
+	}
+
+	for idx := range blackouts {
+		if !blackouts[idx].Covers(job) {
+			continue
+		}
+		if open, until := blackouts[idx].Until(t); open && until.After(end) {
+			window, end = &blackouts[idx], until
+		}
+	}
+
+	return window, end
+}
+
+// hold
+// Handles a run that is due during a blackout window according to the job's misfire
+// policy. A job that skips misfires has the run dropped and recorded in its history,
+// otherwise the run is queued to start once the window closes. Returns true if the
+// run was deferred.
+func (scheduler *Scheduler) hold(trigger *Trigger, window *interfaces.Blackout, end time.Time, now time.Time) bool {
+
+	policy := trigger.Job.Misfire.GetPolicy()
+
+	if policy == interfaces.MisfireSkip {
+		run := database.Run{
+			Job:       trigger.Job.Identifier,
+			Scheduled: trigger.Scheduled,
+			Started:   now,
+			Finished:  now,
+			Attempt:   trigger.Attempt,
+			Status:    database.RunSkipped,
+			Error:     fmt.Sprintf("blackout window %s", window.Name),
+			Blackout:  window.Name,
+		}
+		if err := scheduler.History.Create(run); err != nil {
+			log.Println(err)
+		}
+
+		// a run-at job only has one run, so it will never fire again
+		if trigger.Job.IsOneShot() {
+			if err := scheduler.Jobs.MarkFinished(trigger.Job.Identifier, database.RunSkipped); err != nil {
+				log.Println(err)
+			}
+		}
+
+		return false
+	}
+
+	trigger.Eligible = end
+	trigger.Blackout = window.Name
+
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	// runs deferred by a window are treated as misfires, so a job that runs
+	// once keeps only its latest run and a job that runs all keeps its limit
+	deferred := make([]int, 0)
+	for idx, queued := range scheduler.queue {
+		if (queued.Job.Identifier == trigger.Job.Identifier) && (queued.Blackout != "") {
+			deferred = append(deferred, idx)
+		}
+	}
+
+	limit := 1
+	if policy == interfaces.MisfireRunAll {
+		limit = trigger.Job.Misfire.GetLimit()
+	}
+
+	for len(deferred) >= limit {
+		idx := deferred[0]
+		scheduler.queue = append(scheduler.queue[:idx], scheduler.queue[idx+1:]...)
+		deferred = deferred[1:]
+		for i := range deferred {
+			deferred[i]--
+		}
+	}
+
+	scheduler.queue = append(scheduler.queue, trigger)
+
+	return true
+}
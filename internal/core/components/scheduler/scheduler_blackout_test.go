@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"github.com/GabeCordo/cluster-tools/internal/core/components/database"
+	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+	"testing"
+	"time"
+)
+
+var testBlackout = &interfaces.Blackout{
+	Name:  "month-end",
+	Start: time.Date(2024, time.March, 31, 18, 0, 0, 0, time.UTC),
+	End:   time.Date(2024, time.April, 1, 6, 0, 0, 0, time.UTC),
+}
+
+func TestScheduler_Blackout(t *testing.T) {
+
+	scheduler, _ := New(database.NewLocalJobDatabase())
+	if err := scheduler.Blackouts.Create(testBlackout); err != nil {
+		t.Error(err)
+		return
+	}
+
+	scheduled := testBlackout.Start.Add(time.Hour)
+	window, end := scheduler.Blackout(testJob, scheduled)
+	if (window == nil) || !end.Equal(testBlackout.End) {
+		t.Error("expected the job to be blacked out until the window ends")
+		return
+	}
+
+	// the test job skips misfires, so the run is dropped and recorded
+	trigger := &Trigger{Job: *testJob, Scheduled: scheduled, Attempt: 1}
+	if scheduler.hold(trigger, window, end, scheduled) {
+		t.Error("expected the run to be skipped")
+	}
+
+	runs, _ := scheduler.History.Get(testJob.Identifier)
+	if (len(runs) != 1) || (runs[0].Status != database.RunSkipped) || (runs[0].Blackout != testBlackout.Name) {
+		t.Error("expected the skipped run to be recorded with the window that skipped it")
+	}
+
+	if scheduler.ItemsInQueue() != 0 {
+		t.Error("expected the skipped run not to be queued")
+	}
+}
+
+func TestScheduler_Blackout2(t *testing.T) {
+
+	scheduler, _ := New(database.NewLocalJobDatabase())
+	scheduler.Blackouts.Create(testBlackout)
+
+	job := *testJob
+	job.Misfire = interfaces.Misfire{Policy: interfaces.MisfireRunOnce}
+
+	for _, scheduled := range []time.Time{testBlackout.Start.Add(time.Hour), testBlackout.Start.Add(2 * time.Hour)} {
+		window, end := scheduler.Blackout(&job, scheduled)
+		if !scheduler.hold(&Trigger{Job: job, Scheduled: scheduled, Attempt: 1}, window, end, scheduled) {
+			t.Error("expected the run to be deferred")
+		}
+	}
+
+	queue := scheduler.GetQueue()
+	if len(queue) != 1 {
+		t.Error("expected a job that runs once to keep a single deferred run")
+		return
+	}
+
+	if !queue[0].Eligible.Equal(testBlackout.End) || !queue[0].Scheduled.Equal(testBlackout.Start.Add(2*time.Hour)) {
+		t.Error("expected the latest run to wait for the window to close")
+	}
+}
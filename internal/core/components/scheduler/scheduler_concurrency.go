@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"errors"
+	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+)
+
+var RunStillActive = errors.New("a previous run of the job is still active")
+
+// Admit
+// Decides if a queued run of the job can be provisioned according to its concurrency
+// policy. When the policy is replace, the active supervisors that need to be cancelled
+// before the new run starts are returned.
+func (scheduler *Scheduler) Admit(job *interfaces.Job) (admit bool, replace []uint64) {
+
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	active := scheduler.active[job.Identifier]
+
+	switch job.GetConcurrencyPolicy() {
+	case interfaces.ConcurrencyForbid:
+		return len(active) == 0, nil
+	case interfaces.ConcurrencyReplace:
+		replace = make([]uint64, len(active))
+		copy(replace, active)
+		for _, supervisor := range replace {
+			scheduler.replaced[supervisor] = true
+		}
+		return true, replace
+	default:
+		return true, nil
+	}
+}
+
+// Started
+// Records that a supervisor was provisioned for a run of the job with the identifier.
+func (scheduler *Scheduler) Started(identifier string, supervisor uint64) {
+
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	scheduler.active[identifier] = append(scheduler.active[identifier], supervisor)
+}
+
+// Finished
+// Records that a supervisor provisioned for the job with the identifier reached a
+// terminal state. Returns true if the supervisor was cancelled to make way for a
+// newer run, in which case its outcome should not be treated as the job's outcome.
+func (scheduler *Scheduler) Finished(identifier string, supervisor uint64) (replaced bool) {
+
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	active := scheduler.active[identifier]
+	for idx, id := range active {
+		if id == supervisor {
+			active = append(active[:idx], active[idx+1:]...)
+			break
+		}
+	}
+
+	if len(active) == 0 {
+		delete(scheduler.active, identifier)
+	} else {
+		scheduler.active[identifier] = active
+	}
+
+	replaced = scheduler.replaced[supervisor]
+	delete(scheduler.replaced, supervisor)
+
+	return replaced
+}
+
+// Running
+// Returns the supervisors that are active for the job with the identifier.
+func (scheduler *Scheduler) Running(identifier string) []uint64 {
+
+	scheduler.mutex.RLock()
+	defer scheduler.mutex.RUnlock()
+
+	running := make([]uint64, len(scheduler.active[identifier]))
+	copy(running, scheduler.active[identifier])
+
+	return running
+}
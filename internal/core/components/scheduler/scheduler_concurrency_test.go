@@ -0,0 +1,54 @@
+package scheduler
+
+import (
+	"github.com/GabeCordo/cluster-tools/internal/core/components/database"
+	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+	"testing"
+)
+
+func TestScheduler_Admit(t *testing.T) {
+
+	scheduler, _ := New(database.NewLocalJobDatabase())
+
+	job := &interfaces.Job{Identifier: "test", Concurrency: interfaces.ConcurrencyForbid}
+
+	if admit, _ := scheduler.Admit(job); !admit {
+		t.Error("expected a job without active supervisors to be admitted")
+	}
+
+	scheduler.Started(job.Identifier, 1)
+	if admit, _ := scheduler.Admit(job); admit {
+		t.Error("expected the forbid policy to skip a run while a supervisor is active")
+	}
+
+	scheduler.Finished(job.Identifier, 1)
+	if admit, _ := scheduler.Admit(job); !admit {
+		t.Error("expected the job to be admitted once its supervisor finished")
+	}
+}
+
+func TestScheduler_Admit2(t *testing.T) {
+
+	scheduler, _ := New(database.NewLocalJobDatabase())
+
+	job := &interfaces.Job{Identifier: "test"}
+	scheduler.Started(job.Identifier, 1)
+
+	if admit, replace := scheduler.Admit(job); !admit || (len(replace) != 0) {
+		t.Error("expected the allow policy to run alongside the active supervisor")
+	}
+
+	job.Concurrency = interfaces.ConcurrencyReplace
+	admit, replace := scheduler.Admit(job)
+	if !admit || (len(replace) != 1) || (replace[0] != 1) {
+		t.Error("expected the replace policy to cancel the active supervisor")
+	}
+
+	if !scheduler.Finished(job.Identifier, 1) {
+		t.Error("expected the cancelled supervisor to be marked as replaced")
+	}
+
+	if len(scheduler.Running(job.Identifier)) != 0 {
+		t.Error("expected no supervisors to be active")
+	}
+}
@@ -8,41 +8,199 @@ import (
 )
 
 // Watch
-// Checks to see if a job should be added to the schedulers execution queue.
-func Watch(scheduler *Scheduler) {
+// Checks to see if a job should be added to the schedulers execution queue. The function
+// is called after the last fired time of a job moves, so it can be persisted right away.
+func Watch(scheduler *Scheduler, f func()) {
 
 	// every minute we will see if the Jobs need to be run
 	for {
 
-		// TODO: at the moment this only works with minute scheduling
+		now := time.Now()
+		fired := false
 
 		jobs, _ := scheduler.Jobs.GetAll()
-		for idx, job := range jobs {
+		for idx := range jobs {
 
-			if interfaces.IsTimeToRun(&job) {
-				scheduler.mutex.Lock()
-				scheduler.queue = append(scheduler.queue, &jobs[idx])
-				scheduler.mutex.Unlock()
+			job := &jobs[idx]
+
+			runs, latest, missed := due(job, now)
+
+			// a paused job still moves its last fired time forward, so
+			// the runs it skipped are not treated as misfires on resume,
+			// a paused run-at job waits to fire until it is resumed
+			if job.Paused {
+				if job.IsOneShot() {
+					continue
+				}
+				runs, missed = nil, 0
+			}
+
+			if missed > 0 {
+				log.Printf("job %s missed %d run(s), following the %s misfire policy\n",
+					job.Identifier, missed, job.Misfire.GetPolicy())
 			}
+
+			previous := job.LastFired
+			for _, scheduled := range runs {
+				// each run gets its own copy of the job in the queue
+				trigger := &Trigger{Job: *job, Scheduled: scheduled, Previous: previous, Attempt: 1, Eligible: now}
+				previous = scheduled
+
+				scheduler.enqueue(trigger, now)
+			}
+
+			// remember the latest slot even if it was skipped, so the same
+			// missed runs are not considered again on the next check
+			if !latest.IsZero() {
+				if err := scheduler.Jobs.MarkFired(job.Identifier, latest); err != nil {
+					log.Println(err)
+				} else {
+					fired = true
+				}
+			}
+		}
+
+		if fired {
+			f()
+		}
+
+		// wake up at the start of the next minute so the checks do not drift
+		time.Sleep(time.Until(now.Truncate(time.Minute).Add(time.Minute)))
+	}
+}
+
+// enqueue
+// Adds the trigger to the queue unless a blackout window is open at the time it was
+// scheduled, in which case it is deferred or skipped according to the job's misfire
+// policy. Returns false if the trigger was skipped.
+func (scheduler *Scheduler) enqueue(trigger *Trigger, now time.Time) bool {
+
+	job := &trigger.Job
+
+	if window, end := scheduler.Blackout(job, trigger.Scheduled); window != nil {
+		if scheduler.hold(trigger, window, end, now) {
+			log.Printf("job %s deferred until %s by blackout window %s\n",
+				job.Identifier, end.Format(time.RFC3339), window.Name)
+			return true
+		}
+		log.Printf("job %s skipped by blackout window %s\n", job.Identifier, window.Name)
+		return false
+	}
+
+	scheduler.mutex.Lock()
+	scheduler.queue = append(scheduler.queue, trigger)
+	scheduler.mutex.Unlock()
+
+	return true
+}
+
+// due
+// Returns the slots a job should run for at the time now according to its misfire
+// policy, the latest slot the job was scheduled on and how many slots were missed.
+// A job that has never fired can only be due on the current minute.
+func due(job *interfaces.Job, now time.Time) (runs []time.Time, latest time.Time, missed int) {
+
+	// jobs that only depend on other jobs are queued by Upstream
+	if !job.IsTimeTriggered() {
+		return nil, latest, 0
+	}
+
+	// a run-at job fires once as soon as its time has passed, even
+	// if the core was down at the time, and never after it fired
+	if job.IsOneShot() {
+		if job.Finished || !job.LastFired.IsZero() || job.RunAt.After(now) {
+			return nil, latest, 0
+		}
+		return []time.Time{job.RunAt}, job.RunAt, 0
+	}
+
+	cron, err := job.Cron()
+	if err != nil {
+		return nil, latest, 0
+	}
+
+	// the schedule is evaluated on the wall clock of the job's timezone
+	loc, err := job.Location()
+	if err != nil {
+		return nil, latest, 0
+	}
+	now = now.In(loc)
+
+	current := now.Truncate(time.Minute)
+
+	from := job.LastFired.In(loc)
+	if from.IsZero() || from.After(now) {
+		from = current.Add(-time.Nanosecond)
+	}
+
+	keep := 1
+	if job.Misfire.GetPolicy() == interfaces.MisfireRunAll {
+		keep = job.Misfire.GetLimit()
+	}
+
+	// only the most recent slots are kept, older slots beyond the
+	// misfire limit are counted as missed but never run
+	slots := make([]time.Time, 0, keep)
+	total := 0
+	for next := cron.Next(from); !next.IsZero() && !next.After(now); next = cron.Next(next) {
+		if len(slots) == keep {
+			slots = slots[1:]
 		}
+		slots = append(slots, next)
+		total++
+	}
 
-		time.Sleep(1 * time.Minute)
+	if total == 0 {
+		return nil, latest, 0
 	}
+
+	latest = slots[len(slots)-1]
+	onTime := latest.Equal(current)
+
+	missed = total
+	if onTime {
+		missed--
+	}
+
+	switch job.Misfire.GetPolicy() {
+	case interfaces.MisfireRunAll:
+		runs = slots
+	case interfaces.MisfireRunOnce:
+		runs = []time.Time{latest}
+	default:
+		if onTime {
+			runs = []time.Time{latest}
+		}
+	}
+
+	return runs, latest, missed
 }
 
 // Loop
 // Monitors the Job queue and executes the function if a job is found.
-func Loop(scheduler *Scheduler, f func(job *interfaces.Job) error) (err error) {
+func Loop(scheduler *Scheduler, f func(trigger *Trigger) error) (err error) {
 
 	// loop over the job queue until one of the elements hits an error
 	for {
 
-		scheduler.mutex.RLock()
-		if len(scheduler.queue) >= 1 {
-			// pop the first element of the queue (FIFO) and remove the
-			// first element by slicing out the first element
-			popped := scheduler.queue[0]
-			scheduler.queue = scheduler.queue[1:]
+		var popped *Trigger
+
+		scheduler.mutex.Lock()
+		// pop the first element of the queue (FIFO) that is eligible to run,
+		// triggers waiting to be retried stay in place until their delay passes
+		now := time.Now()
+		for idx, trigger := range scheduler.queue {
+			if !trigger.Eligible.After(now) {
+				popped = trigger
+				scheduler.queue = append(scheduler.queue[:idx], scheduler.queue[idx+1:]...)
+				break
+			}
+		}
+		scheduler.mutex.Unlock()
+
+		// the lock is not held while the function runs as it waits on
+		// other threads that may need to update the scheduler
+		if popped != nil {
 
 			// outdated;
 			// to abide by the pattern, if the called function returns an
@@ -53,15 +211,19 @@ func Loop(scheduler *Scheduler, f func(job *interfaces.Job) error) (err error) {
 			//		 the scheduler no longer operates? it doesn't.
 			if err = f(popped); err != nil {
 				log.Println(err)
-				// outdated:
 				// if we receive a non-nil code, an error has occurred, so re-append
 				// the popped job to the back of the queue to try again later
-				//if err != nil {
-				//	scheduler.queue = append(scheduler.queue, popped)
-				//}
+				if retried := scheduler.Retry(popped, time.Now()); retried != nil {
+					log.Printf("job %s will be retried at %s (attempt %d of %d)\n", retried.Job.Identifier,
+						retried.Eligible.Format(time.RFC3339), retried.Attempt, retried.Job.Retry.GetMaxAttempts())
+				} else if popped.Job.IsOneShot() {
+					// a run-at job that ran out of attempts will never fire again
+					if err := scheduler.Jobs.MarkFinished(popped.Job.Identifier, database.RunFailed); err != nil {
+						log.Println(err)
+					}
+				}
 			}
 		}
-		scheduler.mutex.RUnlock()
 
 		// the time till the next queue check is defined in the Scheduler config
 		time.Sleep(time.Duration(scheduler.config.RefreshInterval) * time.Millisecond)
@@ -70,17 +232,37 @@ func Loop(scheduler *Scheduler, f func(job *interfaces.Job) error) (err error) {
 	return err
 }
 
-func (scheduler *Scheduler) GetQueue() []interfaces.Job {
+// Retry
+// Re-queues a trigger that failed to be provisioned if the job's retry policy has
+// attempts left. Returns the re-queued trigger or nil if the trigger was dropped.
+func (scheduler *Scheduler) Retry(trigger *Trigger, now time.Time) *Trigger {
+
+	if trigger.Attempt >= trigger.Job.Retry.GetMaxAttempts() {
+		return nil
+	}
+
+	retried := *trigger
+	retried.Eligible = now.Add(trigger.Job.Retry.Delay(trigger.Attempt))
+	retried.Attempt++
+
+	scheduler.mutex.Lock()
+	scheduler.queue = append(scheduler.queue, &retried)
+	scheduler.mutex.Unlock()
+
+	return &retried
+}
+
+func (scheduler *Scheduler) GetQueue() []Trigger {
 
 	scheduler.mutex.RLock()
 	defer scheduler.mutex.RUnlock()
 
-	jobs := make([]interfaces.Job, len(scheduler.queue))
-	for idx, job := range scheduler.queue {
-		jobs[idx] = *job // make a copy
+	triggers := make([]Trigger, len(scheduler.queue))
+	for idx, trigger := range scheduler.queue {
+		triggers[idx] = *trigger // make a copy
 	}
 
-	return jobs
+	return triggers
 }
 
 func (scheduler *Scheduler) Print() {
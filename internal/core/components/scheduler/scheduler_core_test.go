@@ -4,6 +4,7 @@ import (
 	"github.com/GabeCordo/cluster-tools/internal/core/components/database"
 	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
 	"testing"
+	"time"
 )
 
 var testInterval = &interfaces.Interval{
@@ -220,3 +221,199 @@ func TestScheduler_Delete(t *testing.T) {
 		t.Error("wrong job was deleted")
 	}
 }
+
+func TestScheduler_Due(t *testing.T) {
+
+	job := &interfaces.Job{Identifier: "nightly", Module: "common", Cluster: "vec", Schedule: "0 2 * * *"}
+
+	// the job has never fired, so only the current minute is considered
+	now := time.Date(2024, time.March, 4, 2, 0, 30, 0, time.UTC)
+	runs, latest, missed := due(job, now)
+	if (len(runs) != 1) || (missed != 0) || !latest.Equal(now.Truncate(time.Minute)) {
+		t.Error("expected a job that never fired to run on the current minute")
+	}
+
+	now = time.Date(2024, time.March, 4, 3, 0, 0, 0, time.UTC)
+	if runs, _, _ = due(job, now); len(runs) != 0 {
+		t.Error("expected a job that never fired not to catch up")
+	}
+}
+
+func TestScheduler_Due2(t *testing.T) {
+
+	job := &interfaces.Job{Identifier: "nightly", Module: "common", Cluster: "vec", Schedule: "0 2 * * *"}
+	job.LastFired = time.Date(2024, time.March, 1, 2, 0, 0, 0, time.UTC)
+
+	// the core was down for the runs on the 2nd, 3rd and 4th
+	now := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+
+	runs, latest, missed := due(job, now)
+	if (len(runs) != 0) || (missed != 3) {
+		t.Error("expected the skip policy to drop every missed run")
+	}
+	if !latest.Equal(time.Date(2024, time.March, 4, 2, 0, 0, 0, time.UTC)) {
+		t.Error("expected the latest slot to be the run on the 4th")
+	}
+
+	job.Misfire.Policy = interfaces.MisfireRunOnce
+	if runs, _, _ = due(job, now); (len(runs) != 1) || !runs[0].Equal(latest) {
+		t.Error("expected the run-once policy to coalesce the missed runs")
+	}
+
+	job.Misfire = interfaces.Misfire{Policy: interfaces.MisfireRunAll, Limit: 2}
+	runs, _, missed = due(job, now)
+	if (len(runs) != 2) || (missed != 3) {
+		t.Error("expected the run-all policy to run the 2 most recent missed slots")
+	}
+
+	job.LastFired = latest
+	if runs, _, missed = due(job, now); (len(runs) != 0) || (missed != 0) {
+		t.Error("expected no runs once the latest slot has fired")
+	}
+}
+
+func TestScheduler_Pause(t *testing.T) {
+
+	jobs := database.NewLocalJobDatabase()
+	jobs.Create(testJob)
+
+	if err := jobs.SetPaused(testJob.Identifier, true); err != nil {
+		t.Error(err)
+		return
+	}
+
+	path := t.TempDir()
+	if err := jobs.Save(path); err != nil {
+		t.Error(err)
+		return
+	}
+
+	reloaded := database.NewLocalJobDatabase()
+	if err := reloaded.Load(path); err != nil {
+		t.Error(err)
+		return
+	}
+
+	loaded, _ := reloaded.GetAll()
+	if (len(loaded) != 1) || !loaded[0].Paused {
+		t.Error("expected the paused state to survive a save and load")
+	}
+}
+
+func TestScheduler_Due3(t *testing.T) {
+
+	if _, err := time.LoadLocation("Asia/Tokyo"); err != nil {
+		t.Skip("timezone database is not available")
+	}
+
+	job := &interfaces.Job{Identifier: "test", Schedule: "0 9 * * *", Timezone: "Asia/Tokyo"}
+
+	// 09:00 in Tokyo is midnight UTC
+	now := time.Date(2024, time.March, 4, 0, 0, 30, 0, time.UTC)
+	if runs, _, _ := due(job, now); len(runs) != 1 {
+		t.Error("expected the job to be due on the wall clock of its timezone")
+	}
+
+	job.Timezone = ""
+	if runs, _, _ := due(job, now.In(time.FixedZone("UTC+3", 3*60*60))); len(runs) != 0 {
+		t.Error("expected the job not to be due outside of its timezone")
+	}
+}
+
+func TestScheduler_Due4(t *testing.T) {
+
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("timezone database is not available")
+	}
+
+	job := &interfaces.Job{Identifier: "test", Schedule: "30 1 * * *", Timezone: "America/New_York"}
+
+	// the first 01:30 is in daylight time
+	first := time.Date(2024, time.November, 3, 5, 30, 0, 0, time.UTC)
+	runs, latest, _ := due(job, first)
+	if len(runs) != 1 {
+		t.Error("expected the job to run the first time 01:30 occurs")
+		return
+	}
+	job.LastFired = latest
+
+	// an hour later the clocks have fallen back and it is 01:30 again
+	second := first.Add(time.Hour)
+	if second.In(loc).Hour() != 1 {
+		t.Error("expected the wall clock to repeat the hour")
+	}
+
+	if runs, _, _ := due(job, second); len(runs) != 0 {
+		t.Error("expected the job not to run again when the hour repeats")
+	}
+}
+
+func TestScheduler_Retry(t *testing.T) {
+
+	scheduler, _ := New(database.NewLocalJobDatabase())
+
+	job := *testJob
+	job.Retry = interfaces.Retry{MaxAttempts: 2, InitialDelay: 5}
+
+	now := time.Now()
+	trigger := &Trigger{Job: job, Scheduled: now, Attempt: 1, Eligible: now}
+
+	retried := scheduler.Retry(trigger, now)
+	if retried == nil {
+		t.Error("expected the trigger to be retried")
+		return
+	}
+
+	if (retried.Attempt != 2) || !retried.Eligible.Equal(now.Add(5*time.Second)) {
+		t.Error("expected the retry to be the second attempt and wait for the initial delay")
+	}
+
+	if queue := scheduler.GetQueue(); (len(queue) != 1) || (queue[0].Attempt != 2) {
+		t.Error("expected the retried attempt to be visible in the queue")
+	}
+
+	if scheduler.Retry(retried, now) != nil {
+		t.Error("expected the trigger to be dropped after the max attempts")
+	}
+}
+
+func TestScheduler_Due5(t *testing.T) {
+
+	runAt := time.Date(2024, time.March, 5, 2, 0, 0, 0, time.UTC)
+	job := &interfaces.Job{Identifier: "backfill", RunAt: runAt}
+
+	if runs, _, _ := due(job, runAt.Add(-time.Minute)); len(runs) != 0 {
+		t.Error("expected a run-at job not to fire before its time")
+	}
+
+	// the core was down at 02:00, the job should still fire once it is back
+	runs, latest, _ := due(job, runAt.Add(time.Hour))
+	if (len(runs) != 1) || !latest.Equal(runAt) {
+		t.Error("expected a run-at job to fire once its time has passed")
+	}
+
+	job.LastFired = latest
+	if runs, _, _ := due(job, runAt.Add(2*time.Hour)); len(runs) != 0 {
+		t.Error("expected a run-at job to fire only once")
+	}
+}
+
+func TestTrigger_Metadata(t *testing.T) {
+
+	scheduled := time.Date(2024, time.March, 5, 2, 0, 0, 0, time.UTC)
+	job := interfaces.Job{Identifier: "nightly", Metadata: map[string]string{
+		"window": `{{date "2006-01-02" .PreviousRun}}..{{date "2006-01-02" .ScheduledTime}}`,
+	}}
+	trigger := &Trigger{Job: job, Scheduled: scheduled, Previous: scheduled.Add(-24 * time.Hour), Attempt: 1}
+
+	metadata, err := trigger.Metadata()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if metadata["window"] != "2024-03-04..2024-03-05" {
+		t.Error("expected the metadata to be rendered against the trigger")
+	}
+}
@@ -0,0 +1,146 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+	"time"
+)
+
+var DependencyCycle = errors.New("job dependencies form a cycle")
+
+// Create
+// Adds a new job to the scheduler if its depends-on edges do not introduce
+// a cycle into the dependency graph of the existing jobs.
+func (scheduler *Scheduler) Create(job *interfaces.Job) error {
+
+	if err := job.Validate(); err != nil {
+		return err
+	}
+
+	jobs, err := scheduler.Jobs.GetAll()
+	if err != nil {
+		return err
+	}
+
+	if err = CheckDependencies(append(jobs, *job)); err != nil {
+		return err
+	}
+
+	return scheduler.Jobs.Create(job)
+}
+
+// CheckDependencies
+// Returns an error if the depends-on edges between the jobs form a cycle.
+func CheckDependencies(jobs []interfaces.Job) error {
+
+	edges := make(map[string][]string)
+	for _, job := range jobs {
+		edges[job.Identifier] = job.DependsOn
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+
+	var visit func(identifier string, path []string) error
+	visit = func(identifier string, path []string) error {
+
+		switch state[identifier] {
+		case visiting:
+			return fmt.Errorf("%w: %v", DependencyCycle, append(path, identifier))
+		case visited:
+			return nil
+		}
+
+		state[identifier] = visiting
+		for _, upstream := range edges[identifier] {
+			if err := visit(upstream, append(path, identifier)); err != nil {
+				return err
+			}
+		}
+		state[identifier] = visited
+
+		return nil
+	}
+
+	for identifier := range edges {
+		if err := visit(identifier, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Upstream
+// Called when a supervisor provisioned for the job with the identifier reaches a
+// terminal state. Dependents whose upstream jobs have all completed are queued,
+// if the upstream did not complete its dependents are skipped and returned.
+// Paused dependents are ignored. Queued dependents go through the same blackout
+// windows as scheduled runs, and the same concurrency policy once they are popped.
+func (scheduler *Scheduler) Upstream(identifier string, completed bool) (queued, skipped []interfaces.Job) {
+
+	jobs, _ := scheduler.Jobs.GetAll()
+	now := time.Now()
+
+	// a dependent's previous run is the last one in its history
+	previous := func(identifier string) time.Time {
+		runs, err := scheduler.History.Get(identifier)
+		if (err != nil) || (len(runs) == 0) {
+			return time.Time{}
+		}
+		return runs[len(runs)-1].Scheduled
+	}
+
+	ready := make([]*Trigger, 0)
+
+	scheduler.mutex.Lock()
+
+	for idx := range jobs {
+
+		dependent := jobs[idx]
+		if !dependent.DependsOnJob(identifier) || dependent.Paused {
+			continue
+		}
+
+		// a failed upstream resets the runs the dependent has been waiting on
+		if !completed {
+			delete(scheduler.upstreams, dependent.Identifier)
+			skipped = append(skipped, dependent)
+			continue
+		}
+
+		satisfied, found := scheduler.upstreams[dependent.Identifier]
+		if !found {
+			satisfied = make(map[string]bool)
+			scheduler.upstreams[dependent.Identifier] = satisfied
+		}
+		satisfied[identifier] = true
+
+		all := true
+		for _, upstream := range dependent.DependsOn {
+			all = all && satisfied[upstream]
+		}
+
+		if all {
+			delete(scheduler.upstreams, dependent.Identifier)
+			ready = append(ready, &Trigger{
+				Job: dependent, Scheduled: now, Previous: previous(dependent.Identifier), Attempt: 1, Eligible: now})
+		}
+	}
+
+	// the lock is released first as a blackout window needs it to defer the trigger
+	scheduler.mutex.Unlock()
+
+	for _, trigger := range ready {
+		if scheduler.enqueue(trigger, now) {
+			queued = append(queued, trigger.Job)
+		}
+	}
+
+	return queued, skipped
+}
@@ -0,0 +1,161 @@
+package scheduler
+
+import (
+	"errors"
+	"github.com/GabeCordo/cluster-tools/internal/core/components/database"
+	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+	"testing"
+	"time"
+)
+
+func dependentJob(identifier string, dependsOn ...string) *interfaces.Job {
+	return &interfaces.Job{
+		Identifier: identifier,
+		Module:     "common",
+		Cluster:    "vec",
+		Config:     "vec",
+		DependsOn:  dependsOn,
+		Metadata:   make(map[string]string),
+	}
+}
+
+func TestCheckDependencies(t *testing.T) {
+
+	jobs := []interfaces.Job{
+		*dependentJob("a", "c"),
+		*dependentJob("b", "a"),
+		*dependentJob("c", "b"),
+	}
+
+	if err := CheckDependencies(jobs); !errors.Is(err, DependencyCycle) {
+		t.Error("expected a -> c -> b -> a to be detected as a cycle")
+	}
+
+	jobs[0].DependsOn = nil
+	jobs[0].Schedule = "@daily"
+	if err := CheckDependencies(jobs); err != nil {
+		t.Error("expected a chain of dependencies to be accepted")
+	}
+}
+
+func TestScheduler_Create2(t *testing.T) {
+
+	scheduler, _ := New(database.NewLocalJobDatabase())
+
+	first := dependentJob("first")
+	first.Schedule = "@daily"
+	if err := scheduler.Create(first); err != nil {
+		t.Error(err)
+	}
+
+	if err := scheduler.Create(dependentJob("second", "first")); err != nil {
+		t.Error(err)
+	}
+
+	if err := scheduler.Create(dependentJob("third", "third")); err == nil {
+		t.Error("expected a job depending on itself to be rejected")
+	}
+
+	scheduled := dependentJob("fourth", "first")
+	scheduled.Schedule = "@hourly"
+	if err := scheduler.Create(scheduled); err == nil {
+		t.Error("expected a dependent that also has a schedule to be rejected")
+	}
+}
+
+func TestScheduler_Upstream(t *testing.T) {
+
+	scheduler, _ := New(database.NewLocalJobDatabase())
+
+	for _, identifier := range []string{"a", "b"} {
+		upstream := dependentJob(identifier)
+		upstream.Schedule = "@daily"
+		scheduler.Create(upstream)
+	}
+	scheduler.Create(dependentJob("c", "a", "b"))
+
+	if queued, _ := scheduler.Upstream("a", true); len(queued) != 0 {
+		t.Error("expected the dependent to wait for all of its upstream jobs")
+	}
+
+	if queued, _ := scheduler.Upstream("b", true); (len(queued) != 1) || (queued[0].Identifier != "c") {
+		t.Error("expected the dependent to be queued once all upstream jobs completed")
+	}
+
+	if scheduler.ItemsInQueue() != 1 {
+		t.Error("expected the dependent to be added to the queue")
+	}
+}
+
+func TestScheduler_Upstream2(t *testing.T) {
+
+	scheduler, _ := New(database.NewLocalJobDatabase())
+
+	upstream := dependentJob("a")
+	upstream.Schedule = "@daily"
+	scheduler.Create(upstream)
+
+	dependent := dependentJob("b", "a")
+	dependent.OnUpstreamCrash = interfaces.UpstreamAlert
+	scheduler.Create(dependent)
+
+	queued, skipped := scheduler.Upstream("a", false)
+	if len(queued) != 0 {
+		t.Error("expected a crashed upstream not to queue its dependents")
+	}
+
+	if (len(skipped) != 1) || (skipped[0].GetUpstreamPolicy() != interfaces.UpstreamAlert) {
+		t.Error("expected the dependent to be skipped with the alert policy")
+	}
+}
+
+func TestScheduler_Upstream3(t *testing.T) {
+
+	scheduler, _ := New(database.NewLocalJobDatabase())
+
+	upstream := dependentJob("a")
+	upstream.Schedule = "@daily"
+	scheduler.Create(upstream)
+
+	dependent := dependentJob("b", "a")
+	dependent.Paused = true
+	scheduler.Create(dependent)
+
+	if queued, _ := scheduler.Upstream("a", true); len(queued) != 0 {
+		t.Error("expected a paused dependent not to be queued")
+	}
+}
+
+func TestScheduler_Upstream4(t *testing.T) {
+
+	scheduler, _ := New(database.NewLocalJobDatabase())
+
+	now := time.Now()
+	scheduler.Blackouts.Create(&interfaces.Blackout{Name: "freeze", Start: now.Add(-time.Hour), End: now.Add(time.Hour)})
+
+	upstream := dependentJob("a")
+	upstream.Schedule = "@daily"
+	scheduler.Create(upstream)
+
+	skipped := dependentJob("b", "a")
+	scheduler.Create(skipped)
+
+	deferred := dependentJob("c", "a")
+	deferred.Cluster = "other"
+	deferred.Misfire = interfaces.Misfire{Policy: interfaces.MisfireRunOnce}
+	scheduler.Create(deferred)
+
+	queued, _ := scheduler.Upstream("a", true)
+	if (len(queued) != 1) || (queued[0].Identifier != "c") {
+		t.Error("expected the blackout window to skip the dependent that skips misfires")
+	}
+
+	runs, _ := scheduler.History.Get("b")
+	if (len(runs) != 1) || (runs[0].Blackout != "freeze") {
+		t.Error("expected the skipped dependent to be recorded with the window that skipped it")
+	}
+
+	if queue := scheduler.GetQueue(); (len(queue) != 1) || (queue[0].Blackout != "freeze") || !queue[0].Eligible.After(now) {
+		t.Error("expected the other dependent to be deferred until the window closes")
+	}
+}
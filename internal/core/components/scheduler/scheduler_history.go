@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"github.com/GabeCordo/cluster-tools/internal/core/components/database"
+	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+	"time"
+)
+
+// Record
+// Adds a run to the history of the triggered job. A run that could not be
+// provisioned is stored as failed with the error that stopped it.
+func (scheduler *Scheduler) Record(trigger *Trigger, supervisor uint64, started time.Time, err error) error {
+
+	run := database.Run{
+		Job:        trigger.Job.Identifier,
+		Supervisor: supervisor,
+		Scheduled:  trigger.Scheduled,
+		Started:    started,
+		Attempt:    trigger.Attempt,
+		Status:     database.RunActive,
+		Blackout:   trigger.Blackout,
+	}
+
+	if err != nil {
+		run.Status = database.RunFailed
+		run.Error = err.Error()
+		run.Finished = started
+	}
+
+	return scheduler.History.Create(run)
+}
+
+// Skip
+// Adds a run that was dropped before a supervisor was provisioned to the history of the
+// triggered job, with the error that stopped it. A run-at job only has one run, so it is
+// marked finished.
+func (scheduler *Scheduler) Skip(trigger *Trigger, at time.Time, err error) error {
+
+	run := database.Run{
+		Job:       trigger.Job.Identifier,
+		Scheduled: trigger.Scheduled,
+		Started:   at,
+		Finished:  at,
+		Attempt:   trigger.Attempt,
+		Status:    database.RunSkipped,
+		Error:     err.Error(),
+		Blackout:  trigger.Blackout,
+	}
+
+	if e := scheduler.History.Create(run); e != nil {
+		return e
+	}
+
+	if trigger.Job.IsOneShot() {
+		return scheduler.Jobs.MarkFinished(trigger.Job.Identifier, database.RunSkipped)
+	}
+
+	return nil
+}
+
+// Complete
+// Stores the final status of the supervisor provisioned for a run of the job.
+// A run-at job is marked finished with the status as its result.
+func (scheduler *Scheduler) Complete(identifier string, supervisor uint64, status string) error {
+
+	if err := scheduler.History.Finish(identifier, supervisor, status, time.Now()); err != nil {
+		return err
+	}
+
+	jobs, err := scheduler.Jobs.GetBy(&interfaces.Filter{Identifier: identifier})
+	if (err != nil) || (len(jobs) == 0) || !jobs[0].IsOneShot() {
+		return err
+	}
+
+	return scheduler.Jobs.MarkFinished(identifier, status)
+}
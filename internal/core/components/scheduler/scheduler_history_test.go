@@ -0,0 +1,90 @@
+package scheduler
+
+import (
+	"errors"
+	"github.com/GabeCordo/cluster-tools/internal/core/components/database"
+	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+	"testing"
+	"time"
+)
+
+func TestScheduler_Record(t *testing.T) {
+
+	scheduler, _ := New(database.NewLocalJobDatabase())
+
+	scheduled := time.Date(2024, time.March, 4, 2, 0, 0, 0, time.UTC)
+	trigger := &Trigger{Job: *testJob, Scheduled: scheduled}
+
+	if err := scheduler.Record(trigger, 1, scheduled.Add(time.Second), nil); err != nil {
+		t.Error(err)
+	}
+
+	if err := scheduler.Complete(testJob.Identifier, 1, "completed"); err != nil {
+		t.Error(err)
+	}
+
+	runs, _ := scheduler.History.Get(testJob.Identifier)
+	if len(runs) != 1 {
+		t.Error("expected a single run to be recorded")
+		return
+	}
+
+	if (runs[0].Status != "completed") || (runs[0].Supervisor != 1) || !runs[0].Scheduled.Equal(scheduled) {
+		t.Error("expected the run to link the scheduled time to the supervisor and its outcome")
+	}
+
+	if runs[0].Duration <= 0 {
+		t.Error("expected the run to have a duration once it finished")
+	}
+}
+
+func TestScheduler_Record2(t *testing.T) {
+
+	scheduler, _ := New(database.NewLocalJobDatabase())
+
+	trigger := &Trigger{Job: *testJob, Scheduled: time.Now()}
+	scheduler.Record(trigger, 0, time.Now(), errors.New("module does not exist"))
+
+	runs, _ := scheduler.History.Get(testJob.Identifier)
+	if (len(runs) != 1) || (runs[0].Status != database.RunFailed) || (runs[0].Error == "") {
+		t.Error("expected a run that could not be provisioned to be recorded as failed")
+	}
+}
+
+func TestScheduler_Skip(t *testing.T) {
+
+	scheduler, _ := New(database.NewLocalJobDatabase())
+
+	trigger := &Trigger{Job: *testJob, Scheduled: time.Now(), Attempt: 1}
+	if err := scheduler.Skip(trigger, time.Now(), RunStillActive); err != nil {
+		t.Error(err)
+	}
+
+	runs, _ := scheduler.History.Get(testJob.Identifier)
+	if (len(runs) != 1) || (runs[0].Status != database.RunSkipped) || (runs[0].Error != RunStillActive.Error()) {
+		t.Error("expected a run skipped by the concurrency policy to be recorded with the reason")
+	}
+}
+
+func TestScheduler_Complete(t *testing.T) {
+
+	scheduler, _ := New(database.NewLocalJobDatabase())
+
+	job := *testJob
+	job.Interval = interfaces.Interval{}
+	job.RunAt = time.Now()
+	if err := scheduler.Create(&job); err != nil {
+		t.Error(err)
+		return
+	}
+
+	scheduler.Record(&Trigger{Job: job, Scheduled: job.RunAt, Attempt: 1}, 1, time.Now(), nil)
+	if err := scheduler.Complete(job.Identifier, 1, "completed"); err != nil {
+		t.Error(err)
+	}
+
+	jobs, _ := scheduler.Jobs.GetAll()
+	if !jobs[0].Finished || (jobs[0].Result != "completed") {
+		t.Error("expected the run-at job to be marked finished with its result")
+	}
+}
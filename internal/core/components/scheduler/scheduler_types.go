@@ -4,6 +4,7 @@ import (
 	"github.com/GabeCordo/cluster-tools/internal/core/components/database"
 	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
 	"sync"
+	"time"
 )
 
 // Config
@@ -13,21 +14,43 @@ type Config struct {
 	RefreshInterval int `yaml:"refresh_interval"` // how often the scheduler will check to see if new Jobs should be added to the run queue.
 }
 
+// Trigger
+// A run of a job waiting in the scheduler's queue.
+type Trigger struct {
+	Job       interfaces.Job `json:"job"`
+	Scheduled time.Time      `json:"scheduled"`          // the time the run was due
+	Previous  time.Time      `json:"previous"`           // the previous time the job was due
+	Attempt   int            `json:"attempt"`            // starts at 1, incremented each time provisioning is retried
+	Eligible  time.Time      `json:"eligible"`           // the trigger is not popped from the queue before this time
+	Blackout  string         `json:"blackout,omitempty"` // the blackout window the run was deferred by
+}
+
 // Scheduler
 // Contains a collection of Jobs that are run on fixed intervals.
 type Scheduler struct {
-	Jobs   database.JobDatabase // A static list of Jobs registered to the scheduler.
-	queue  []*interfaces.Job    // A dynamic list of Jobs waiting to be run.
-	config Config               // Dynamic information that tells the Scheduler how to run.
-	mutex  sync.RWMutex
+	Jobs      database.JobDatabase      // A static list of Jobs registered to the scheduler.
+	History   database.HistoryDatabase  // A record of every run the scheduler triggered.
+	Blackouts database.BlackoutDatabase // The windows of time jobs must not fire in.
+	queue     []*Trigger                // A dynamic list of Jobs waiting to be run.
+	config    Config                    // Dynamic information that tells the Scheduler how to run.
+	mutex     sync.RWMutex
+
+	upstreams map[string]map[string]bool // The completed upstream jobs each dependent is waiting on.
+	active    map[string][]uint64        // The supervisors provisioned for each job that have not finished.
+	replaced  map[uint64]bool            // The supervisors cancelled by a job's replace concurrency policy.
 }
 
 // New
 // Creates a new scheduler and initializes default fields.
-func New(database database.JobDatabase) (*Scheduler, error) {
+func New(jobs database.JobDatabase) (*Scheduler, error) {
 	scheduler := new(Scheduler)
-	scheduler.Jobs = database
-	scheduler.queue = make([]*interfaces.Job, 0)
+	scheduler.Jobs = jobs
+	scheduler.History = database.NewLocalHistoryDatabase()
+	scheduler.Blackouts = database.NewLocalBlackoutDatabase()
+	scheduler.queue = make([]*Trigger, 0)
+	scheduler.upstreams = make(map[string]map[string]bool)
+	scheduler.active = make(map[string][]uint64)
+	scheduler.replaced = make(map[uint64]bool)
 	scheduler.config.RefreshInterval = 1 // ms
 	return scheduler, nil
 }
@@ -38,3 +61,17 @@ func (scheduler *Scheduler) ItemsInQueue() int {
 
 	return len(scheduler.queue)
 }
+
+// Metadata
+// Renders the job's metadata templates for this run of the job.
+func (trigger *Trigger) Metadata() (map[string]string, error) {
+
+	return trigger.Job.RenderMetadata(interfaces.MetadataContext{
+		Job:           trigger.Job.Identifier,
+		Module:        trigger.Job.Module,
+		Cluster:       trigger.Job.Cluster,
+		ScheduledTime: trigger.Scheduled,
+		PreviousRun:   trigger.Previous,
+		Attempt:       trigger.Attempt,
+	})
+}
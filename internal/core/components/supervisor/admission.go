@@ -0,0 +1,216 @@
+package supervisor
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Limits
+// The max supervisors of a cluster, of a module and on each processor that can run at
+// once. A limit of zero, or a processor missing from the map, does not restrict them.
+type Limits struct {
+	Cluster    int            `json:"cluster,omitempty"`
+	Module     int            `json:"module,omitempty"`
+	Processors map[string]int `json:"processors,omitempty"` // by processor name
+}
+
+// Ticket
+// A supervisor waiting for a slot under the limits of its cluster, module and processor.
+// The processors the supervisor can run on are tried in the order they are given.
+type Ticket struct {
+	Supervisor *Supervisor
+	Priority   int // higher priorities are admitted first
+	Queued     time.Time
+	Processors []string
+	Limits     Limits
+}
+
+// Waiting
+// A ticket in the admission queue as it is shown to the operator.
+type Waiting struct {
+	Supervisor uint64    `json:"id"`
+	Module     string    `json:"module"`
+	Cluster    string    `json:"cluster"`
+	Priority   int       `json:"priority"`
+	Queued     time.Time `json:"queued"`
+	Wait       float64   `json:"wait"` // seconds the supervisor has waited so far
+}
+
+// usage
+// The supervisors holding a slot by cluster, module and processor.
+type usage struct {
+	clusters   map[string]int // by module/cluster
+	modules    map[string]int
+	processors map[string]int
+}
+
+func (usage *usage) add(module, cluster, processor string) {
+
+	usage.clusters[module+"/"+cluster]++
+	usage.modules[module]++
+	usage.processors[processor]++
+}
+
+// under
+// Returns true if another supervisor can be admitted with the count already running.
+func under(limit, count int) bool {
+	return (limit <= 0) || (count < limit)
+}
+
+// fits
+// Returns the first processor of the ticket with a free slot, and false if the cluster,
+// the module or every processor is at its limit.
+func (ticket *Ticket) fits(usage *usage) (string, bool) {
+
+	module, cluster := ticket.Supervisor.Module, ticket.Supervisor.Cluster
+
+	if !under(ticket.Limits.Cluster, usage.clusters[module+"/"+cluster]) ||
+		!under(ticket.Limits.Module, usage.modules[module]) {
+		return "", false
+	}
+
+	for _, processor := range ticket.Processors {
+		if under(ticket.Limits.Processors[processor], usage.processors[processor]) {
+			return processor, true
+		}
+	}
+
+	return "", false
+}
+
+// Admission
+// Queues the supervisors that are over a concurrency limit, ordered by priority and then
+// by the time they were queued, until a slot frees up for them.
+type Admission struct {
+	registry *Registry
+	tickets  []*Ticket
+
+	// how many supervisors were admitted from the queue and how long they waited in total
+	admitted uint64
+	waited   time.Duration
+
+	mutex sync.Mutex
+}
+
+func NewAdmission(registry *Registry) *Admission {
+
+	admission := new(Admission)
+	admission.registry = registry
+	admission.tickets = make([]*Ticket, 0)
+	return admission
+}
+
+// Enqueue
+// Moves the supervisor of the ticket to queued and adds the ticket behind every other
+// ticket of the same, or a higher, priority.
+func (admission *Admission) Enqueue(ticket *Ticket) error {
+
+	if _, err := ticket.Supervisor.Event(Queue); err != nil {
+		return err
+	}
+
+	admission.mutex.Lock()
+	defer admission.mutex.Unlock()
+
+	idx := sort.Search(len(admission.tickets), func(i int) bool {
+		return admission.tickets[i].Priority < ticket.Priority
+	})
+
+	admission.tickets = append(admission.tickets, nil)
+	copy(admission.tickets[idx+1:], admission.tickets[idx:])
+	admission.tickets[idx] = ticket
+
+	return nil
+}
+
+// Admit
+// Removes the tickets that fit under their limits from the queue, highest priority first,
+// and moves their supervisors back to created on the processor they were admitted to. A
+// ticket over its limits does not hold back the tickets behind it that still fit.
+func (admission *Admission) Admit(now time.Time) []*Ticket {
+
+	admission.mutex.Lock()
+	defer admission.mutex.Unlock()
+
+	admitted := make([]*Ticket, 0)
+	if len(admission.tickets) == 0 {
+		return admitted
+	}
+
+	usage := admission.registry.usage()
+	remaining := make([]*Ticket, 0, len(admission.tickets))
+
+	for _, ticket := range admission.tickets {
+
+		processor, ok := ticket.fits(usage)
+		if !ok {
+			remaining = append(remaining, ticket)
+			continue
+		}
+
+		// the supervisor was cancelled while it waited
+		if err := ticket.Supervisor.admit(processor, now); err != nil {
+			continue
+		}
+
+		usage.add(ticket.Supervisor.Module, ticket.Supervisor.Cluster, processor)
+		admitted = append(admitted, ticket)
+
+		admission.admitted++
+		admission.waited += now.Sub(ticket.Queued)
+	}
+
+	admission.tickets = remaining
+
+	return admitted
+}
+
+// Remove
+// Drops the ticket of the supervisor from the queue, returns false if it was not queued.
+func (admission *Admission) Remove(id uint64) bool {
+
+	admission.mutex.Lock()
+	defer admission.mutex.Unlock()
+
+	for idx, ticket := range admission.tickets {
+		if ticket.Supervisor.Id == id {
+			admission.tickets = append(admission.tickets[:idx], admission.tickets[idx+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Waiting
+// Returns the tickets in the queue in the order they will be considered for admission.
+func (admission *Admission) Waiting(now time.Time) []Waiting {
+
+	admission.mutex.Lock()
+	defer admission.mutex.Unlock()
+
+	waiting := make([]Waiting, len(admission.tickets))
+	for idx, ticket := range admission.tickets {
+		waiting[idx] = Waiting{
+			Supervisor: ticket.Supervisor.Id,
+			Module:     ticket.Supervisor.Module,
+			Cluster:    ticket.Supervisor.Cluster,
+			Priority:   ticket.Priority,
+			Queued:     ticket.Queued,
+			Wait:       now.Sub(ticket.Queued).Seconds(),
+		}
+	}
+
+	return waiting
+}
+
+// Admitted
+// Returns how many supervisors were admitted from the queue and how long they waited in total.
+func (admission *Admission) Admitted() (uint64, time.Duration) {
+
+	admission.mutex.Lock()
+	defer admission.mutex.Unlock()
+
+	return admission.admitted, admission.waited
+}
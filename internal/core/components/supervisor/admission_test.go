@@ -0,0 +1,103 @@
+package supervisor
+
+import (
+	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+	"testing"
+	"time"
+)
+
+func enqueue(t *testing.T, registry *Registry, admission *Admission, priority int, queued time.Time, limits Limits, processors ...string) *Supervisor {
+
+	id := registry.Create("", ModuleName, ClusterName, &interfaces.Config{Identifier: ClusterName})
+	supervisor, _ := registry.Get(id)
+
+	ticket := &Ticket{Supervisor: supervisor, Priority: priority, Queued: queued, Processors: processors, Limits: limits}
+	if err := admission.Enqueue(ticket); err != nil {
+		t.Fatal(err)
+	}
+
+	return supervisor
+}
+
+func TestAdmission_Admit(t *testing.T) {
+
+	registry := NewRegistry()
+	admission := NewAdmission(registry)
+
+	now := time.Date(2024, time.March, 5, 2, 0, 0, 0, time.UTC)
+	limits := Limits{Cluster: 1}
+
+	low := enqueue(t, registry, admission, 0, now, limits, ProcessorName)
+	high := enqueue(t, registry, admission, 5, now.Add(time.Second), limits, ProcessorName)
+
+	admitted := admission.Admit(now.Add(time.Minute))
+	if (len(admitted) != 1) || (admitted[0].Supervisor != high) {
+		t.Error("expected the supervisor with the highest priority to be admitted first")
+	}
+
+	if (high.GetStatus() != Created) || (high.Processor != ProcessorName) {
+		t.Error("expected the admitted supervisor to be created on its processor")
+	}
+
+	if len(admission.Admit(now.Add(time.Minute))) != 0 {
+		t.Error("expected the cluster limit to hold back the queued supervisor")
+	}
+
+	high.Event(Start)
+	high.Event(Complete)
+
+	if admitted = admission.Admit(now.Add(time.Minute)); (len(admitted) != 1) || (admitted[0].Supervisor != low) {
+		t.Error("expected the queued supervisor to be admitted once the slot is freed")
+	}
+
+	if count, waited := admission.Admitted(); (count != 2) || (waited != 119*time.Second) {
+		t.Error("expected the admission to record how long the supervisors waited")
+	}
+}
+
+func TestAdmission_Admit2(t *testing.T) {
+
+	registry := NewRegistry()
+	admission := NewAdmission(registry)
+
+	now := time.Now()
+	limits := Limits{Processors: map[string]int{"first": 1}}
+
+	first := enqueue(t, registry, admission, 0, now, limits, "first", "second")
+	second := enqueue(t, registry, admission, 0, now, limits, "first", "second")
+
+	if admitted := admission.Admit(now); len(admitted) != 2 {
+		t.Error("expected both supervisors to be admitted")
+	}
+
+	if (first.Processor != "first") || (second.Processor != "second") {
+		t.Error("expected the next processor to be used once the first is at its limit")
+	}
+}
+
+func TestAdmission_Remove(t *testing.T) {
+
+	registry := NewRegistry()
+	admission := NewAdmission(registry)
+
+	now := time.Now()
+	limits := Limits{Module: 1}
+
+	running := enqueue(t, registry, admission, 0, now, limits, ProcessorName)
+	admission.Admit(now)
+	running.Event(Start)
+
+	queued := enqueue(t, registry, admission, 0, now, limits, ProcessorName)
+	if waiting := admission.Waiting(now); (len(waiting) != 1) || (waiting[0].Supervisor != queued.Id) {
+		t.Error("expected the supervisor over the module limit to be waiting")
+	}
+
+	queued.Event(Cancel)
+	if !admission.Remove(queued.Id) || (len(admission.Waiting(now)) != 0) {
+		t.Error("expected the cancelled supervisor to be removed from the queue")
+	}
+
+	if queued.GetStatus() != Cancelled {
+		t.Error("expected a queued supervisor to be cancelled without being provisioned")
+	}
+}
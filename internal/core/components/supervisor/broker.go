@@ -0,0 +1,123 @@
+package supervisor
+
+import (
+	"github.com/GabeCordo/cluster-tools/internal/core/components/messenger"
+	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+	"sync"
+	"time"
+)
+
+type UpdateKind string
+
+const (
+	TransitionUpdate UpdateKind = "transition"
+	StatisticsUpdate            = "statistics"
+	LogUpdate                   = "log"
+)
+
+const (
+	DefaultSubscriptionBuffer = 64
+)
+
+// Update
+// A change to a supervisor pushed to the clients following it.
+type Update struct {
+	Kind       UpdateKind                `json:"kind"`
+	Supervisor uint64                    `json:"id"`
+	Module     string                    `json:"module"`
+	Cluster    string                    `json:"cluster"`
+	At         time.Time                 `json:"at"`
+	Transition *Transition               `json:"transition,omitempty"`
+	Statistics *interfaces.Statistics    `json:"statistics,omitempty"`
+	Level      messenger.MessagePriority `json:"level,omitempty"`
+	Message    string                    `json:"message,omitempty"`
+}
+
+// Subscription
+// The updates of the supervisors matching a filter. Updates are dropped, rather than
+// blocking the supervisor thread, if the subscriber falls too far behind.
+type Subscription struct {
+	Updates chan Update
+	Filter  Filter
+
+	broker *Broker
+	once   sync.Once
+}
+
+// Close
+// Stops the subscription from receiving updates and closes its channel.
+func (subscription *Subscription) Close() {
+
+	subscription.once.Do(func() {
+		subscription.broker.remove(subscription)
+	})
+}
+
+// Broker
+// Fans out the updates of supervisors to their subscriptions.
+type Broker struct {
+	subscriptions map[*Subscription]bool
+	mutex         sync.RWMutex
+}
+
+func NewBroker() *Broker {
+
+	broker := new(Broker)
+	broker.subscriptions = make(map[*Subscription]bool)
+	return broker
+}
+
+func (broker *Broker) Subscribe(filter Filter) *Subscription {
+
+	subscription := &Subscription{
+		Updates: make(chan Update, DefaultSubscriptionBuffer),
+		Filter:  filter,
+		broker:  broker,
+	}
+
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	broker.subscriptions[subscription] = true
+	return subscription
+}
+
+func (broker *Broker) remove(subscription *Subscription) {
+
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	delete(broker.subscriptions, subscription)
+	close(subscription.Updates)
+}
+
+// Publish
+// Sends the update to every subscription whose filter matches the supervisor.
+func (broker *Broker) Publish(update Update) {
+
+	broker.mutex.RLock()
+	defer broker.mutex.RUnlock()
+
+	for subscription := range broker.subscriptions {
+
+		if !subscription.Filter.Matches(update.Supervisor, update.Module, update.Cluster) {
+			continue
+		}
+
+		select {
+		case subscription.Updates <- update:
+		default:
+			// the subscriber is too slow, it can catch up with GET /supervisor
+		}
+	}
+}
+
+// Count
+// Returns the number of open subscriptions.
+func (broker *Broker) Count() int {
+
+	broker.mutex.RLock()
+	defer broker.mutex.RUnlock()
+
+	return len(broker.subscriptions)
+}
@@ -0,0 +1,57 @@
+package supervisor
+
+import (
+	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+	"testing"
+)
+
+func TestBroker_Publish(t *testing.T) {
+
+	registry := NewRegistry()
+	config := &interfaces.Config{Identifier: ClusterName}
+
+	followed := registry.Create(ProcessorName, ModuleName, ClusterName, config)
+	other := registry.Create(ProcessorName, ModuleName, ClusterName, config)
+
+	byId := registry.Broker.Subscribe(Filter{Id: followed})
+	byCluster := registry.Broker.Subscribe(Filter{Module: ModuleName, Cluster: ClusterName})
+
+	for _, id := range []uint64{followed, other} {
+		supervisor, _ := registry.Get(id)
+		supervisor.Event(Start)
+	}
+
+	if update := <-byId.Updates; (update.Supervisor != followed) || (update.Transition.To != Active) {
+		t.Error("expected the transition of the followed supervisor to be published")
+	}
+
+	if len(byId.Updates) != 0 {
+		t.Error("expected the transitions of other supervisors to be filtered out")
+	}
+
+	if len(byCluster.Updates) != 2 {
+		t.Error("expected every supervisor of the cluster to be published")
+	}
+
+	byId.Close()
+	byId.Close() // closing twice is safe
+
+	if _, ok := <-byId.Updates; ok || (registry.Broker.Count() != 1) {
+		t.Error("expected a closed subscription to be removed")
+	}
+}
+
+func TestBroker_Publish2(t *testing.T) {
+
+	broker := NewBroker()
+	subscription := broker.Subscribe(Filter{})
+
+	// a subscriber that never reads must not block the publisher
+	for i := 0; i < DefaultSubscriptionBuffer+10; i++ {
+		broker.Publish(Update{Kind: LogUpdate, Supervisor: 1, Message: "hello"})
+	}
+
+	if len(subscription.Updates) != DefaultSubscriptionBuffer {
+		t.Error("expected updates past the buffer of a slow subscriber to be dropped")
+	}
+}
@@ -1,5 +1,12 @@
 package supervisor
 
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
 func (filter Filter) UseId() bool {
 	return filter.Id != 0
 }
@@ -11,3 +18,140 @@ func (filter Filter) UseModule() bool {
 func (filter Filter) UseCluster() bool {
 	return (filter.Module != "") && (filter.Cluster != "")
 }
+
+// Empty
+// Returns true if the filter has no criteria, so it selects every supervisor.
+func (filter Filter) Empty() bool {
+	return (filter.Module == "") && (filter.Cluster == "") && (filter.Id == 0) && (len(filter.Status) == 0) &&
+		(filter.Processor == "") && filter.CreatedAfter.IsZero() && filter.CreatedBefore.IsZero() &&
+		filter.FinishedAfter.IsZero() && filter.FinishedBefore.IsZero() && (filter.Metadata == "") &&
+		filter.Selector.Empty()
+}
+
+// Matches
+// Returns true if the supervisor is selected by the filter, an id is preferred over the
+// module and cluster, and an empty filter matches every supervisor.
+func (filter Filter) Matches(id uint64, module, cluster string) bool {
+
+	if filter.UseId() {
+		return filter.Id == id
+	}
+
+	if filter.UseCluster() {
+		return (filter.Module == module) && (filter.Cluster == cluster)
+	}
+
+	if filter.UseModule() {
+		return filter.Module == module
+	}
+
+	return true
+}
+
+// ParseStatus
+// Returns the status with the name, or false if no status has the name.
+func ParseStatus(name string) (Status, bool) {
+
+	switch status := Status(name); status {
+	case Created, Queued, Active, Crashed, Completed, Terminated, Cancelling, Cancelled, Unknown:
+		return status, true
+	}
+
+	return "", false
+}
+
+// Selects
+// Returns true if the supervisor matches every field of the filter.
+func (filter Filter) Selects(supervisor *Supervisor) bool {
+
+	supervisor.mutex.RLock()
+	defer supervisor.mutex.RUnlock()
+
+	if !filter.Matches(supervisor.Id, supervisor.Module, supervisor.Cluster) {
+		return false
+	}
+
+	if len(filter.Status) > 0 {
+		found := false
+		for _, status := range filter.Status {
+			found = found || (status == supervisor.Status)
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if (filter.Processor != "") && (filter.Processor != supervisor.Processor) {
+		return false
+	}
+
+	if !within(supervisor.created(), filter.CreatedAfter, filter.CreatedBefore) {
+		return false
+	}
+
+	if !filter.FinishedAfter.IsZero() || !filter.FinishedBefore.IsZero() {
+		finished := supervisor.finished()
+		if finished.IsZero() || !within(finished, filter.FinishedAfter, filter.FinishedBefore) {
+			return false
+		}
+	}
+
+	if filter.Metadata != "" {
+		key, value, useValue := strings.Cut(filter.Metadata, "=")
+		actual, found := supervisor.Metadata[key]
+		if !found || (useValue && (actual != value)) {
+			return false
+		}
+	}
+
+	return filter.Selector.Matches(supervisor.Labels)
+}
+
+// within
+// Returns true if t is not before the after time and is before the before time, a zero
+// time leaves that side of the range open.
+func within(t, after, before time.Time) bool {
+
+	if !after.IsZero() && t.Before(after) {
+		return false
+	}
+
+	if !before.IsZero() && !t.Before(before) {
+		return false
+	}
+
+	return true
+}
+
+// after
+// Returns the identifier the cursor of the filter continues after, or zero for the first page.
+func (filter Filter) after() (uint64, error) {
+
+	if filter.Cursor == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseUint(filter.Cursor, 10, 64)
+	if (err != nil) || (id == 0) {
+		return 0, InvalidCursor
+	}
+
+	return id, nil
+}
+
+func (filter Filter) limit() int {
+
+	if filter.Limit == Unlimited {
+		return math.MaxInt
+	}
+
+	if filter.Limit <= 0 {
+		return DefaultPageSize
+	}
+
+	if filter.Limit > MaxPageSize {
+		return MaxPageSize
+	}
+
+	return filter.Limit
+}
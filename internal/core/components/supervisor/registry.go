@@ -2,9 +2,19 @@ package supervisor
 
 import (
 	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+	"sort"
+	"strconv"
+	"time"
 )
 
 func (registry *Registry) Create(processorName, moduleName, clusterName string, conf *interfaces.Config) (identifier uint64) {
+	return registry.Provision(processorName, moduleName, clusterName, conf, Details{})
+}
+
+// Provision
+// Creates a supervisor with the details of the request that provisioned it. The record is
+// complete before it is added to the registry, so queries never see it partly filled in.
+func (registry *Registry) Provision(processorName, moduleName, clusterName string, conf *interfaces.Config, details Details) (identifier uint64) {
 
 	registry.mutex.Lock()
 	defer registry.mutex.Unlock()
@@ -12,6 +22,14 @@ func (registry *Registry) Create(processorName, moduleName, clusterName string,
 	identifier = registry.counter
 
 	supervisor := newSupervisor(identifier, processorName, moduleName, clusterName, conf)
+	supervisor.Job = details.Job
+	supervisor.Metadata = details.Metadata
+	supervisor.Labels = details.Labels
+	supervisor.Original = details.Original
+	supervisor.Attempt = details.Attempt
+	supervisor.Priority = details.Priority
+	supervisor.IdempotencyKey = details.IdempotencyKey
+	supervisor.broker = registry.Broker
 	registry.supervisors[identifier] = supervisor
 
 	registry.counter++
@@ -19,6 +37,27 @@ func (registry *Registry) Create(processorName, moduleName, clusterName string,
 	return identifier
 }
 
+// Restore
+// Adds the supervisors stored before the core restarted to the registry, new supervisors
+// continue from the next identifier, or after the highest restored one if that is larger.
+func (registry *Registry) Restore(supervisors []*Supervisor, next uint64) {
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	for _, supervisor := range supervisors {
+		registry.supervisors[supervisor.Id] = supervisor
+		supervisor.broker = registry.Broker
+		if supervisor.Id >= next {
+			next = supervisor.Id + 1
+		}
+	}
+
+	if next > registry.counter {
+		registry.counter = next
+	}
+}
+
 func (registry *Registry) Get(identifier uint64) (supervisor *Supervisor, found bool) {
 
 	registry.mutex.RLock()
@@ -28,6 +67,8 @@ func (registry *Registry) Get(identifier uint64) (supervisor *Supervisor, found
 	return supervisor, found
 }
 
+// GetBy
+// Returns every supervisor the filter selects, newest first.
 func (registry *Registry) GetBy(filter *Filter) []*Supervisor {
 
 	supervisors := make([]*Supervisor, 0)
@@ -42,26 +83,193 @@ func (registry *Registry) GetBy(filter *Filter) []*Supervisor {
 	// if an id is provided we ignore the module and cluster
 	if filter.Id != 0 {
 
-		if supervisor, found := registry.supervisors[filter.Id]; found {
+		if supervisor, found := registry.supervisors[filter.Id]; found && filter.Selects(supervisor) {
 			supervisors = append(supervisors, supervisor)
 		}
 
 		return supervisors
 	}
 
-	useCluster := filter.UseCluster()
-	useModule := filter.UseModule()
-
 	for _, supervisor := range registry.supervisors {
-
-		if useCluster && (supervisor.Cluster == filter.Cluster) && (supervisor.Module == filter.Module) {
-			supervisors = append(supervisors, supervisor)
-		} else if useModule && (supervisor.Module == filter.Module) {
-			supervisors = append(supervisors, supervisor)
-		} else if !useCluster && !useModule {
+		if filter.Selects(supervisor) {
 			supervisors = append(supervisors, supervisor)
 		}
 	}
 
+	sort.Slice(supervisors, func(i, j int) bool {
+		return supervisors[i].Id > supervisors[j].Id
+	})
+
 	return supervisors
 }
+
+// GetByKey
+// Returns the newest supervisor created with the idempotency key since the time. A supervisor
+// that finished without its processor ever starting it does not hold on to its key, so the
+// request can be made again.
+func (registry *Registry) GetByKey(key string, since time.Time) (*Supervisor, bool) {
+
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	var newest *Supervisor
+	for _, supervisor := range registry.supervisors {
+
+		supervisor.mutex.RLock()
+		holds := (supervisor.IdempotencyKey == key) && !supervisor.created().Before(since) &&
+			!(supervisor.isFinished() && supervisor.Started.IsZero())
+		supervisor.mutex.RUnlock()
+
+		if holds && ((newest == nil) || (supervisor.Id > newest.Id)) {
+			newest = supervisor
+		}
+	}
+
+	return newest, newest != nil
+}
+
+// Query
+// Returns a page of the supervisors the filter selects, newest first, starting after the
+// cursor of the filter. The page holds snapshots so it can be read while the supervisors
+// in the registry keep changing.
+func (registry *Registry) Query(filter *Filter) (Page, error) {
+
+	page := Page{Supervisors: make([]*Supervisor, 0)}
+
+	if filter == nil {
+		return page, nil
+	}
+
+	after, err := filter.after()
+	if err != nil {
+		return page, err
+	}
+
+	limit := filter.limit()
+
+	for _, supervisor := range registry.GetBy(filter) {
+
+		if (after != 0) && (supervisor.Id >= after) {
+			continue
+		}
+
+		if len(page.Supervisors) == limit {
+			page.Next = strconv.FormatUint(page.Supervisors[limit-1].Id, 10)
+			break
+		}
+
+		page.Supervisors = append(page.Supervisors, supervisor.Snapshot())
+	}
+
+	return page, nil
+}
+
+// Expired
+// Returns the running supervisors that passed the max runtime of their cluster, or
+// whose processor has been silent for longer than the heartbeat timeout.
+func (registry *Registry) Expired(now time.Time, heartbeat time.Duration) []*Supervisor {
+
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	expired := make([]*Supervisor, 0)
+	for _, supervisor := range registry.supervisors {
+		if supervisor.Expired(now, heartbeat) != nil {
+			expired = append(expired, supervisor)
+		}
+	}
+
+	return expired
+}
+
+// usage
+// Counts the supervisors holding a slot under the concurrency limits, every supervisor
+// that was admitted and has not finished holds one.
+func (registry *Registry) usage() *usage {
+
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	usage := &usage{
+		clusters:   make(map[string]int),
+		modules:    make(map[string]int),
+		processors: make(map[string]int),
+	}
+
+	for _, supervisor := range registry.supervisors {
+		supervisor.mutex.RLock()
+		if !supervisor.isFinished() && (supervisor.Status != Queued) {
+			usage.add(supervisor.Module, supervisor.Cluster, supervisor.Processor)
+		}
+		supervisor.mutex.RUnlock()
+	}
+
+	return usage
+}
+
+// Count
+// Returns the number of supervisors in the registry.
+func (registry *Registry) Count() int {
+
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	return len(registry.supervisors)
+}
+
+// Evict
+// Removes the finished supervisors the retention no longer keeps from the registry, either
+// they finished longer than the max age ago, or their cluster has more newer finished
+// supervisors than the max per cluster. The evicted supervisors are returned by identifier.
+func (registry *Registry) Evict(now time.Time, retention Retention) []*Supervisor {
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	evicted := make([]*Supervisor, 0)
+	if (retention.MaxAge <= 0) && (retention.MaxPerCluster <= 0) {
+		return evicted
+	}
+
+	type finished struct {
+		supervisor *Supervisor
+		at         time.Time
+	}
+
+	clusters := make(map[string][]finished)
+	for _, supervisor := range registry.supervisors {
+		if at := supervisor.Finished(); !at.IsZero() {
+			key := supervisor.Module + "/" + supervisor.Cluster
+			clusters[key] = append(clusters[key], finished{supervisor, at})
+		}
+	}
+
+	maxAge := time.Duration(retention.MaxAge) * time.Minute
+
+	for _, candidates := range clusters {
+
+		// the newest supervisors of the cluster are kept first
+		sort.Slice(candidates, func(i, j int) bool {
+			if candidates[i].at.Equal(candidates[j].at) {
+				return candidates[i].supervisor.Id > candidates[j].supervisor.Id
+			}
+			return candidates[i].at.After(candidates[j].at)
+		})
+
+		for idx, candidate := range candidates {
+			tooMany := (retention.MaxPerCluster > 0) && (idx >= retention.MaxPerCluster)
+			tooOld := (maxAge > 0) && (now.Sub(candidate.at) > maxAge)
+
+			if tooMany || tooOld {
+				delete(registry.supervisors, candidate.supervisor.Id)
+				evicted = append(evicted, candidate.supervisor)
+			}
+		}
+	}
+
+	sort.Slice(evicted, func(i, j int) bool {
+		return evicted[i].Id < evicted[j].Id
+	})
+
+	return evicted
+}
@@ -3,6 +3,7 @@ package supervisor
 import (
 	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
 	"testing"
+	"time"
 )
 
 var (
@@ -37,3 +38,214 @@ func TestRegistry_Get(t *testing.T) {
 		t.Error("supervisor failed to store the correct config record")
 	}
 }
+
+func TestRegistry_Restore(t *testing.T) {
+
+	registry := NewRegistry()
+
+	config := &interfaces.Config{Identifier: ClusterName}
+	restored := []*Supervisor{
+		newSupervisor(4, ProcessorName, ModuleName, ClusterName, config),
+		newSupervisor(7, ProcessorName, ModuleName, ClusterName, config),
+	}
+	registry.Restore(restored, 5)
+
+	if _, found := registry.Get(7); !found {
+		t.Error("expected the restored supervisors to be in the registry")
+	}
+
+	if supervisorId := registry.Create(ProcessorName, ModuleName, ClusterName, config); supervisorId != 8 {
+		t.Errorf("expected the identifiers to continue after the highest restored one but got %d", supervisorId)
+	}
+
+	registry.Restore(nil, 20)
+	if supervisorId := registry.Create(ProcessorName, ModuleName, ClusterName, config); supervisorId != 20 {
+		t.Errorf("expected the identifiers to continue from the stored counter but got %d", supervisorId)
+	}
+}
+
+func TestRegistry_Evict(t *testing.T) {
+
+	registry := NewRegistry()
+	config := &interfaces.Config{Identifier: ClusterName}
+
+	now := time.Date(2024, time.March, 5, 2, 0, 0, 0, time.UTC)
+
+	// five supervisors that finished a minute apart, and one still running
+	for i := 0; i < 6; i++ {
+		id := registry.Create(ProcessorName, ModuleName, ClusterName, config)
+		supervisor, _ := registry.Get(id)
+		supervisor.Event(Start)
+		if i < 5 {
+			supervisor.Event(Complete)
+			supervisor.History[len(supervisor.History)-1].At = now.Add(time.Duration(i-5) * time.Minute)
+		}
+	}
+
+	if evicted := registry.Evict(now, Retention{}); len(evicted) != 0 {
+		t.Error("expected a registry without retention to keep every supervisor")
+	}
+
+	evicted := registry.Evict(now, Retention{MaxPerCluster: 3})
+	if (len(evicted) != 2) || (evicted[0].Id != 1) || (evicted[1].Id != 2) {
+		t.Error("expected the oldest finished supervisors past the max per cluster to be evicted")
+	}
+
+	evicted = registry.Evict(now, Retention{MaxAge: 2})
+	if (len(evicted) != 1) || (evicted[0].Id != 3) {
+		t.Error("expected the supervisors that finished before the max age to be evicted")
+	}
+
+	if _, found := registry.Get(6); !found || (registry.Count() != 3) {
+		t.Error("expected running supervisors to never be evicted")
+	}
+}
+
+func TestRegistry_Query(t *testing.T) {
+
+	registry := NewRegistry()
+	config := &interfaces.Config{Identifier: ClusterName}
+
+	// five supervisors where the odd ones crash
+	for i := 1; i <= 5; i++ {
+		id := registry.Create(ProcessorName, ModuleName, ClusterName, config)
+		supervisor, _ := registry.Get(id)
+		supervisor.Event(Start)
+		if (i % 2) == 1 {
+			supervisor.Event(Error)
+		}
+	}
+
+	page, err := registry.Query(&Filter{Status: []Status{Crashed}, Limit: 2})
+	if (err != nil) || (len(page.Supervisors) != 2) || (page.Supervisors[0].Id != 5) || (page.Next != "3") {
+		t.Error("expected the first page to hold the newest crashed supervisors")
+	}
+
+	page, err = registry.Query(&Filter{Status: []Status{Crashed}, Limit: 2, Cursor: page.Next})
+	if (err != nil) || (len(page.Supervisors) != 1) || (page.Supervisors[0].Id != 1) || (page.Next != "") {
+		t.Error("expected the last page to hold the oldest crashed supervisor")
+	}
+
+	if _, err = registry.Query(&Filter{Cursor: "abc"}); err != InvalidCursor {
+		t.Error("expected a cursor that is not an identifier to be rejected")
+	}
+
+	for i := 0; i < DefaultPageSize; i++ {
+		registry.Create(ProcessorName, ModuleName, ClusterName, config)
+	}
+
+	if page, _ = registry.Query(&Filter{Limit: Unlimited}); (len(page.Supervisors) != DefaultPageSize+5) || (page.Next != "") {
+		t.Error("expected an unlimited query to return every supervisor in one page")
+	}
+}
+
+func TestRegistry_Query2(t *testing.T) {
+
+	registry := NewRegistry()
+	config := &interfaces.Config{Identifier: ClusterName}
+
+	now := time.Date(2024, time.March, 5, 2, 0, 0, 0, time.UTC)
+
+	first := registry.Create(ProcessorName, ModuleName, ClusterName, config)
+	second := registry.Create("other", ModuleName, ClusterName, config)
+
+	supervisor, _ := registry.Get(first)
+	supervisor.History[0].At = now.Add(-time.Hour)
+	supervisor.Metadata = map[string]string{"owner": "ops"}
+
+	supervisor, _ = registry.Get(second)
+	supervisor.History[0].At = now
+
+	page, _ := registry.Query(&Filter{CreatedBefore: now})
+	if (len(page.Supervisors) != 1) || (page.Supervisors[0].Id != first) {
+		t.Error("expected only the supervisors created before the time")
+	}
+
+	page, _ = registry.Query(&Filter{Processor: "other"})
+	if (len(page.Supervisors) != 1) || (page.Supervisors[0].Id != second) {
+		t.Error("expected only the supervisors on the processor")
+	}
+
+	page, _ = registry.Query(&Filter{Metadata: "owner=ops"})
+	if (len(page.Supervisors) != 1) || (page.Supervisors[0].Id != first) {
+		t.Error("expected only the supervisors with the metadata")
+	}
+
+	page, _ = registry.Query(&Filter{FinishedAfter: now.Add(-time.Hour)})
+	if len(page.Supervisors) != 0 {
+		t.Error("expected running supervisors to never match a finished range")
+	}
+}
+
+func TestRegistry_Query3(t *testing.T) {
+
+	registry := NewRegistry()
+	config := &interfaces.Config{Identifier: ClusterName}
+
+	first := registry.Create(ProcessorName, ModuleName, ClusterName, config)
+	second := registry.Create(ProcessorName, ModuleName, ClusterName, config)
+	registry.Create(ProcessorName, ModuleName, ClusterName, config)
+
+	supervisor, _ := registry.Get(first)
+	supervisor.Labels = map[string]string{"team": "billing", "env": "prod"}
+
+	supervisor, _ = registry.Get(second)
+	supervisor.Labels = map[string]string{"team": "billing", "env": "dev"}
+
+	selector, _ := interfaces.ParseSelector("team=billing,env!=dev")
+	page, _ := registry.Query(&Filter{Selector: selector})
+	if (len(page.Supervisors) != 1) || (page.Supervisors[0].Id != first) {
+		t.Error("expected only the supervisors with labels matching the selector")
+	}
+
+	if page.Supervisors[0].Labels["team"] != "billing" {
+		t.Error("expected the labels to be kept on the snapshot")
+	}
+
+	page.Supervisors[0].Labels["team"] = "ops"
+	page.Supervisors[0].Status = Crashed
+
+	if supervisor, _ = registry.Get(first); (supervisor.Labels["team"] != "billing") || (supervisor.GetStatus() != Created) {
+		t.Error("expected the page to hold copies of the supervisors in the registry")
+	}
+}
+
+func TestRegistry_GetByKey(t *testing.T) {
+
+	registry := NewRegistry()
+	config := &interfaces.Config{Identifier: ClusterName}
+
+	now := time.Now()
+
+	id := registry.Provision(ProcessorName, ModuleName, ClusterName, config, Details{IdempotencyKey: "retry"})
+	supervisor, _ := registry.Get(id)
+
+	if found, ok := registry.GetByKey("retry", now.Add(-time.Minute)); !ok || (found.Id != id) {
+		t.Error("expected the supervisor created with the key to be found")
+	}
+
+	if _, ok := registry.GetByKey("retry", now.Add(time.Minute)); ok {
+		t.Error("expected a supervisor created before the window to be ignored")
+	}
+
+	if _, ok := registry.GetByKey("other", now.Add(-time.Minute)); ok {
+		t.Error("expected no supervisor to be found for an unused key")
+	}
+
+	// the processor could not be reached so the supervisor never started
+	supervisor.Event(Cancel)
+	if _, ok := registry.GetByKey("retry", now.Add(-time.Minute)); ok {
+		t.Error("expected a supervisor that never started to give up its key")
+	}
+}
+
+func TestFilter_Empty(t *testing.T) {
+
+	if !(Filter{Limit: Unlimited}).Empty() {
+		t.Error("expected a filter with only a limit to select every supervisor")
+	}
+
+	if (Filter{Status: []Status{Crashed}}).Empty() {
+		t.Error("expected a filter on the status not to be empty")
+	}
+}
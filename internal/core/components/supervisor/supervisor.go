@@ -1,28 +1,46 @@
 package supervisor
 
-func (supervisor *Supervisor) Event(event Event) Status {
+import (
+	"fmt"
+	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+	"time"
+)
+
+// Event
+// Moves the supervisor to the status the event leads to from its current status and
+// records the transition. Returns IllegalTransition, leaving the status unchanged, if
+// the event is not allowed from the current status.
+func (supervisor *Supervisor) Event(event Event) (Status, error) {
 
 	supervisor.mutex.Lock()
 	defer supervisor.mutex.Unlock()
 
-	switch supervisor.Status {
-	case Created:
-		switch event {
-		case Cancel:
-			supervisor.Status = Cancelled
-		case Start:
-			supervisor.Status = Start
-		}
-	case Active:
-		switch event {
-		case Complete:
-			supervisor.Status = Completed
-		case Error:
-			supervisor.Status = Crashed
-		}
+	return supervisor.transition(event, time.Now())
+}
+
+func (supervisor *Supervisor) transition(event Event, at time.Time) (Status, error) {
+
+	next, found := transitions[supervisor.Status][event]
+	if !found {
+		return supervisor.Status, fmt.Errorf("%w (status: %s, event: %s)", IllegalTransition, supervisor.Status, event)
 	}
 
-	return supervisor.Status
+	transition := Transition{Event: event, From: supervisor.Status, To: next, At: at}
+	supervisor.History = append(supervisor.History, transition)
+	supervisor.Status = next
+
+	if supervisor.broker != nil {
+		supervisor.broker.Publish(Update{
+			Kind:       TransitionUpdate,
+			Supervisor: supervisor.Id,
+			Module:     supervisor.Module,
+			Cluster:    supervisor.Cluster,
+			At:         at,
+			Transition: &transition,
+		})
+	}
+
+	return next, nil
 }
 
 func (supervisor *Supervisor) GetStatus() Status {
@@ -33,10 +51,263 @@ func (supervisor *Supervisor) GetStatus() Status {
 	return supervisor.Status
 }
 
+// SetStatus
+// Moves the supervisor to the status reported by its processor through the event that
+// leads to it. Reporting the current status again is not a transition, any other status
+// that can not be reached, ex. the supervisor was terminated before the processor
+// reported, returns IllegalTransition.
+func (supervisor *Supervisor) SetStatus(status Status) error {
+
+	supervisor.mutex.Lock()
+	defer supervisor.mutex.Unlock()
+
+	if (status == supervisor.Status) && !supervisor.isFinished() {
+		return nil
+	}
+
+	for event, next := range transitions[supervisor.Status] {
+		if next == status {
+			_, err := supervisor.transition(event, time.Now())
+			return err
+		}
+	}
+
+	return fmt.Errorf("%w (status: %s, reported: %s)", IllegalTransition, supervisor.Status, status)
+}
+
+// GetHistory
+// Returns a copy of the transitions the supervisor has made, oldest first.
+func (supervisor *Supervisor) GetHistory() []Transition {
+
+	supervisor.mutex.RLock()
+	defer supervisor.mutex.RUnlock()
+
+	history := make([]Transition, len(supervisor.History))
+	copy(history, supervisor.History)
+	return history
+}
+
+// IsFinished
+// Returns true if the supervisor reached a terminal status.
+func (supervisor *Supervisor) IsFinished() bool {
+
+	supervisor.mutex.RLock()
+	defer supervisor.mutex.RUnlock()
+
+	return supervisor.isFinished()
+}
+
+func (supervisor *Supervisor) isFinished() bool {
+
+	_, found := transitions[supervisor.Status]
+	return !found
+}
+
+// IsFinal
+// Returns true if the transition moved the supervisor to a terminal status.
+func (transition Transition) IsFinal() bool {
+
+	_, found := transitions[transition.To]
+	return !found
+}
+
+// Snapshot
+// Returns a copy of the supervisor that can be stored or encoded without holding its lock.
+func (supervisor *Supervisor) Snapshot() *Supervisor {
+
+	supervisor.mutex.RLock()
+	defer supervisor.mutex.RUnlock()
+
+	snapshot := &Supervisor{
+		Id:             supervisor.Id,
+		Status:         supervisor.Status,
+		Processor:      supervisor.Processor,
+		Module:         supervisor.Module,
+		Cluster:        supervisor.Cluster,
+		Job:            supervisor.Job,
+		Priority:       supervisor.Priority,
+		IdempotencyKey: supervisor.IdempotencyKey,
+		Config:         supervisor.Config,
+		Metadata:       clone(supervisor.Metadata),
+		Labels:         clone(supervisor.Labels),
+		Started:        supervisor.Started,
+		LastHeartbeat:  supervisor.LastHeartbeat,
+		Original:       supervisor.Original,
+		Attempt:        supervisor.Attempt,
+		RestartedBy:    supervisor.RestartedBy,
+	}
+
+	if supervisor.Statistics != nil {
+		statistics := *supervisor.Statistics
+		snapshot.Statistics = &statistics
+	}
+
+	if supervisor.Result != nil {
+		result := *supervisor.Result
+		snapshot.Result = &result
+	}
+
+	snapshot.History = make([]Transition, len(supervisor.History))
+	copy(snapshot.History, supervisor.History)
+
+	return snapshot
+}
+
+// clone
+// Returns a copy of the map so a snapshot does not share it with the supervisor.
+func clone(m map[string]string) map[string]string {
+
+	if m == nil {
+		return nil
+	}
+
+	copied := make(map[string]string, len(m))
+	for key, value := range m {
+		copied[key] = value
+	}
+
+	return copied
+}
+
+// Finished
+// Returns the time the supervisor reached a terminal status, or the zero time if it
+// has not finished.
+func (supervisor *Supervisor) Finished() time.Time {
+
+	supervisor.mutex.RLock()
+	defer supervisor.mutex.RUnlock()
+
+	return supervisor.finished()
+}
+
+func (supervisor *Supervisor) finished() time.Time {
+
+	if !supervisor.isFinished() || (len(supervisor.History) == 0) {
+		return time.Time{}
+	}
+	return supervisor.History[len(supervisor.History)-1].At
+}
+
+func (supervisor *Supervisor) created() time.Time {
+
+	if len(supervisor.History) == 0 {
+		return time.Time{}
+	}
+	return supervisor.History[0].At
+}
+
+// Origin
+// Returns the identifier of the first supervisor of the run, a restarted supervisor
+// is linked back to the one that originally crashed.
+func (supervisor *Supervisor) Origin() uint64 {
+
+	supervisor.mutex.RLock()
+	defer supervisor.mutex.RUnlock()
+
+	if supervisor.Original != 0 {
+		return supervisor.Original
+	}
+	return supervisor.Id
+}
+
+// SetResult
+// Attaches the payload the processor handed back when it finished the supervisor.
+func (supervisor *Supervisor) SetResult(result *Result) {
+
+	supervisor.mutex.Lock()
+	defer supervisor.mutex.Unlock()
+
+	supervisor.Result = result
+}
+
+// SetStatistics
+// Replaces the statistics of the supervisor with the latest reported by its processor.
+func (supervisor *Supervisor) SetStatistics(statistics *interfaces.Statistics) {
+
+	supervisor.mutex.Lock()
+	defer supervisor.mutex.Unlock()
+
+	supervisor.Statistics = statistics
+}
+
+// SetRestartedBy
+// Links a crashed supervisor to the supervisor provisioned to restart it.
+func (supervisor *Supervisor) SetRestartedBy(id uint64) {
+
+	supervisor.mutex.Lock()
+	defer supervisor.mutex.Unlock()
+
+	supervisor.RestartedBy = id
+}
+
+// RestartedByID
+// Returns the identifier of the supervisor that restarted this one, or zero if it was
+// not restarted.
+func (supervisor *Supervisor) RestartedByID() uint64 {
+
+	supervisor.mutex.RLock()
+	defer supervisor.mutex.RUnlock()
+
+	return supervisor.RestartedBy
+}
+
+// admit
+// Moves a queued supervisor back to created on the processor it was admitted to.
+func (supervisor *Supervisor) admit(processor string, at time.Time) error {
+
+	supervisor.mutex.Lock()
+	defer supervisor.mutex.Unlock()
+
+	if _, err := supervisor.transition(Admit, at); err != nil {
+		return err
+	}
+	supervisor.Processor = processor
+
+	return nil
+}
+
 func (supervisor *Supervisor) IsRunning() bool {
 
 	supervisor.mutex.RLock()
 	defer supervisor.mutex.RUnlock()
 
 	return supervisor.Status == Active
-}
\ No newline at end of file
+}
+
+// Heartbeat
+// Records that the processor running the supervisor was heard from at the time.
+func (supervisor *Supervisor) Heartbeat(at time.Time) {
+
+	supervisor.mutex.Lock()
+	defer supervisor.mutex.Unlock()
+
+	if supervisor.Started.IsZero() {
+		supervisor.Started = at
+	}
+	supervisor.LastHeartbeat = at
+}
+
+// Expired
+// Returns why a running supervisor should be considered crashed at the time now, either
+// it ran past the max runtime of its cluster or its processor has been silent for longer
+// than the heartbeat timeout. A timeout of zero disables the heartbeat check.
+func (supervisor *Supervisor) Expired(now time.Time, heartbeat time.Duration) error {
+
+	supervisor.mutex.RLock()
+	defer supervisor.mutex.RUnlock()
+
+	if (supervisor.Status != Active) || supervisor.Started.IsZero() {
+		return nil
+	}
+
+	maxRuntime := time.Duration(supervisor.Config.MaxRuntime) * time.Second
+	if (maxRuntime > 0) && (now.Sub(supervisor.Started) > maxRuntime) {
+		return RuntimeExceeded
+	}
+
+	if (heartbeat > 0) && (now.Sub(supervisor.LastHeartbeat) > heartbeat) {
+		return HeartbeatMissed
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package supervisor
+
+import (
+	"errors"
+	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+	"testing"
+	"time"
+)
+
+func TestSupervisor_Event(t *testing.T) {
+
+	supervisor := newSupervisor(1, ProcessorName, ModuleName, ClusterName, &interfaces.Config{})
+	supervisor.Status = Active
+
+	if status, _ := supervisor.Event(Cancel); status != Cancelling {
+		t.Error("expected an active supervisor to wait for its processor when cancelled")
+	}
+
+	if supervisor.IsFinished() {
+		t.Error("expected a cancelling supervisor not to be finished")
+	}
+
+	if status, _ := supervisor.Event(Terminate); status != Terminated {
+		t.Error("expected a cancelling supervisor to be terminated past its deadline")
+	}
+
+	// the processor confirming after the deadline is ignored
+	if !errors.Is(supervisor.SetStatus(Cancelled), IllegalTransition) || (supervisor.GetStatus() != Terminated) {
+		t.Error("expected a terminated supervisor to keep its status")
+	}
+}
+
+func TestSupervisor_Event2(t *testing.T) {
+
+	supervisor := newSupervisor(1, ProcessorName, ModuleName, ClusterName, &interfaces.Config{})
+
+	if status, _ := supervisor.Event(Cancel); status != Cancelled {
+		t.Error("expected a supervisor that was never provisioned to be cancelled immediately")
+	}
+
+	supervisor.Status = Cancelling
+	if status, _ := supervisor.Event(Cancel); status != Cancelled {
+		t.Error("expected the confirmation of the processor to cancel the supervisor")
+	}
+}
+
+func TestSupervisor_Event3(t *testing.T) {
+
+	supervisor := newSupervisor(1, ProcessorName, ModuleName, ClusterName, &interfaces.Config{})
+
+	if status, err := supervisor.Event(Start); (err != nil) || (status != Active) {
+		t.Error("expected a started supervisor to be active")
+	}
+
+	if status, err := supervisor.Event(Start); !errors.Is(err, IllegalTransition) || (status != Active) {
+		t.Error("expected starting an active supervisor to be rejected")
+	}
+
+	if supervisor.SetStatus(Active) != nil {
+		t.Error("expected the processor reporting the current status to be accepted")
+	}
+
+	if !errors.Is(supervisor.SetStatus(Cancelled), IllegalTransition) {
+		t.Error("expected an active supervisor to not be cancelled without waiting on its processor")
+	}
+
+	if supervisor.SetStatus(Completed) != nil {
+		t.Error("expected an active supervisor to be completed")
+	}
+
+	if _, err := supervisor.Event(Error); !errors.Is(err, IllegalTransition) {
+		t.Error("expected a completed supervisor to reject every event")
+	}
+}
+
+func TestSupervisor_Event4(t *testing.T) {
+
+	supervisor := newSupervisor(1, ProcessorName, ModuleName, ClusterName, &interfaces.Config{})
+	supervisor.Status = Active
+
+	if status, _ := supervisor.Event(Lose); (status != Unknown) || supervisor.IsFinished() {
+		t.Error("expected a supervisor lost over a restart to be unknown but not finished")
+	}
+
+	if supervisor.SetStatus(Completed) != nil {
+		t.Error("expected the processor to still be able to report an unknown supervisor")
+	}
+}
+
+func TestSupervisor_GetHistory(t *testing.T) {
+
+	supervisor := newSupervisor(1, ProcessorName, ModuleName, ClusterName, &interfaces.Config{})
+	supervisor.Event(Start)
+	supervisor.Event(Cancel)
+	supervisor.Event(Cancel)
+	supervisor.Event(Complete) // rejected and not recorded
+
+	expected := []Transition{
+		{Event: Create, To: Created},
+		{Event: Start, From: Created, To: Active},
+		{Event: Cancel, From: Active, To: Cancelling},
+		{Event: Cancel, From: Cancelling, To: Cancelled},
+	}
+
+	history := supervisor.GetHistory()
+	if len(history) != len(expected) {
+		t.Errorf("expected %d transitions but got %d", len(expected), len(history))
+		return
+	}
+
+	for idx, transition := range history {
+		if (transition.Event != expected[idx].Event) || (transition.From != expected[idx].From) ||
+			(transition.To != expected[idx].To) || transition.At.IsZero() {
+			t.Errorf("unexpected transition %d %v", idx, transition)
+		}
+	}
+}
+
+func TestSupervisor_Expired(t *testing.T) {
+
+	supervisor := newSupervisor(1, ProcessorName, ModuleName, ClusterName, &interfaces.Config{MaxRuntime: 60})
+	supervisor.Status = Active
+
+	started := time.Date(2024, time.March, 5, 2, 0, 0, 0, time.UTC)
+	supervisor.Heartbeat(started)
+
+	if supervisor.Expired(started.Add(30*time.Second), 0) != nil {
+		t.Error("expected a supervisor within its max runtime not to expire")
+	}
+
+	if supervisor.Expired(started.Add(2*time.Minute), 0) != RuntimeExceeded {
+		t.Error("expected a supervisor past its max runtime to expire")
+	}
+}
+
+func TestSupervisor_Expired2(t *testing.T) {
+
+	supervisor := newSupervisor(1, ProcessorName, ModuleName, ClusterName, &interfaces.Config{})
+	supervisor.Status = Active
+
+	started := time.Date(2024, time.March, 5, 2, 0, 0, 0, time.UTC)
+	supervisor.Heartbeat(started)
+	supervisor.Heartbeat(started.Add(time.Hour))
+
+	if supervisor.Expired(started.Add(24*time.Hour), 0) != nil {
+		t.Error("expected a supervisor without a max runtime or heartbeat timeout to never expire")
+	}
+
+	if supervisor.Expired(started.Add(time.Hour+time.Minute), 5*time.Minute) != nil {
+		t.Error("expected a supervisor heard from recently not to expire")
+	}
+
+	if supervisor.Expired(started.Add(2*time.Hour), 5*time.Minute) != HeartbeatMissed {
+		t.Error("expected a silent supervisor to expire")
+	}
+
+	registry := NewRegistry()
+	registry.supervisors[supervisor.Id] = supervisor
+	if len(registry.Expired(started.Add(2*time.Hour), 5*time.Minute)) != 1 {
+		t.Error("expected the registry to return the silent supervisor")
+	}
+}
+
+func TestSupervisor_SetRestartedBy(t *testing.T) {
+
+	supervisor := newSupervisor(2, ProcessorName, ModuleName, ClusterName, &interfaces.Config{})
+
+	// the restart is linked while the registry may be taking a snapshot of the record
+	done := make(chan struct{})
+	go func() {
+		supervisor.SetRestartedBy(3)
+		close(done)
+	}()
+	supervisor.Snapshot()
+	<-done
+
+	if supervisor.RestartedByID() != 3 {
+		t.Error("expected the crashed supervisor to be linked to its restart")
+	}
+
+	if supervisor.Origin() != 2 {
+		t.Error("expected a supervisor that was not restarted to be the origin of its run")
+	}
+}
@@ -1,38 +1,116 @@
 package supervisor
 
 import (
+	"errors"
 	"github.com/GabeCordo/cluster-tools/internal/core/components/messenger"
 	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
 	"sync"
+	"time"
 )
 
 type Status string
 
 const (
 	Created    Status = "created"
+	Queued            = "queued" // waiting for a slot under the concurrency limits
 	Active            = "active"
 	Crashed           = "crashed"
 	Completed         = "completed"
-	Terminated        = "terminated" // this is legacy
+	Terminated        = "terminated" // the processor never confirmed the supervisor stopped
+	Cancelling        = "cancelling" // waiting for the processor to confirm the supervisor stopped
 	Cancelled         = "cancelled"
+	Unknown           = "unknown" // the core restarted and the processor could not confirm the supervisor
+)
+
+var (
+	DoesNotExist   = errors.New("supervisor does not exist")
+	NotCancellable = errors.New("supervisor has already finished and can not be cancelled")
+
+	IllegalTransition = errors.New("supervisor can not make that transition from its current status")
+	InvalidCursor     = errors.New("cursor does not belong to a page of supervisors")
+
+	IdempotencyKeyReused = errors.New("idempotency key was already used to create a supervisor of another cluster or config")
+
+	RuntimeExceeded = errors.New("supervisor ran longer than the max runtime of its cluster")
+	HeartbeatMissed = errors.New("supervisor has not been heard from by its processor")
 )
 
 type Event string
 
 const (
-	Create   Event = "create"
-	Start          = "start"
-	Cancel         = "cancel"
-	Error          = "error"
-	Complete       = "complete"
+	Create    Event = "create"
+	Start           = "start"
+	Cancel          = "cancel"
+	Error           = "error"
+	Complete        = "complete"
+	Terminate       = "terminate"
+	Lose            = "lose"
+	Queue           = "queue"
+	Admit           = "admit"
 )
 
+// transitions
+// The status a supervisor moves to when it receives an event in its current status, any
+// event missing from the table is an illegal transition. A terminal status has no events.
+var transitions = map[Status]map[Event]Status{
+	Created: {
+		Start:  Active,
+		Queue:  Queued,
+		Cancel: Cancelled,
+		Error:  Crashed,
+		Lose:   Unknown,
+	},
+	// a queued supervisor was never provisioned, so it is cancelled without its processor
+	Queued: {
+		Admit:  Created,
+		Cancel: Cancelled,
+	},
+	Active: {
+		Complete: Completed,
+		Error:    Crashed,
+		Cancel:   Cancelling,
+		Lose:     Unknown,
+	},
+	// the processor may finish the supervisor before it receives the cancellation
+	Cancelling: {
+		Cancel:    Cancelled,
+		Complete:  Completed,
+		Error:     Crashed,
+		Terminate: Terminated,
+		Lose:      Unknown,
+	},
+	// a processor that was unreachable when the core restarted may still report
+	Unknown: {
+		Start:    Active,
+		Complete: Completed,
+		Error:    Crashed,
+		Cancel:   Cancelled,
+	},
+}
+
+// Transition
+// A change in the status of a supervisor and the event that caused it.
+type Transition struct {
+	Event Event     `json:"event"`
+	From  Status    `json:"from,omitempty"`
+	To    Status    `json:"to"`
+	At    time.Time `json:"at"`
+}
+
 type Log struct {
 	Id      uint64                    `json:"id"`
 	Level   messenger.MessagePriority `json:"level"`
 	Message string                    `json:"message"`
 }
 
+// Result
+// The payload a processor hands back when it finishes a supervisor, either the value
+// itself or the key of the cached value holding it.
+type Result struct {
+	Value any    `json:"value,omitempty"`
+	Cache string `json:"cache,omitempty"`
+}
+
 type Supervisor struct {
 	Id     uint64 `json:"id"`
 	Status Status `json:"status,omitempty"`
@@ -40,9 +118,27 @@ type Supervisor struct {
 	Processor string `json:"processor,omitempty"`
 	Module    string `json:"module,omitempty"`
 	Cluster   string `json:"cluster,omitempty"`
+	Job       string `json:"job,omitempty"`      // the scheduled job that provisioned the supervisor
+	Priority  int    `json:"priority,omitempty"` // queued supervisors with a higher priority are admitted first
+
+	IdempotencyKey string `json:"idempotency-key,omitempty"` // repeated create requests with the key return this supervisor
 
 	Config     interfaces.Config      `json:"config,omitempty"`
+	Metadata   map[string]string      `json:"metadata,omitempty"`
+	Labels     map[string]string      `json:"labels,omitempty"` // kept on the record to select supervisors by, unlike metadata
 	Statistics *interfaces.Statistics `json:"statistics"`
+	Result     *Result                `json:"result,omitempty"` // attached by the processor when the supervisor finishes
+
+	Started       time.Time `json:"started,omitempty"`        // the time the processor accepted the supervisor
+	LastHeartbeat time.Time `json:"last-heartbeat,omitempty"` // the last time the processor updated or logged on the supervisor
+
+	Original    uint64 `json:"original,omitempty"`     // the first supervisor of the run if this is a restart
+	Attempt     int    `json:"attempt,omitempty"`      // the restart attempt the supervisor was provisioned for
+	RestartedBy uint64 `json:"restarted-by,omitempty"` // the supervisor provisioned to restart this one after it crashed
+
+	History []Transition `json:"history,omitempty"`
+
+	broker *Broker // transitions are published to the clients following the supervisor
 
 	mutex sync.RWMutex
 }
@@ -51,6 +147,7 @@ func newSupervisor(id uint64, processorName, moduleName, clusterName string, con
 	supervisor := new(Supervisor)
 
 	supervisor.Status = Created
+	supervisor.History = []Transition{{Event: Create, To: Created, At: time.Now()}}
 	supervisor.Id = id
 	supervisor.Processor = processorName
 	supervisor.Module = moduleName
@@ -61,14 +158,65 @@ func newSupervisor(id uint64, processorName, moduleName, clusterName string, con
 	return supervisor
 }
 
+// Details
+// What a supervisor was provisioned for, set on the record before it is added to the registry.
+type Details struct {
+	Job            string
+	Metadata       map[string]string
+	Labels         map[string]string
+	Original       uint64
+	Attempt        int
+	Priority       int
+	IdempotencyKey string
+}
+
+// Retention
+// How long, and how many per cluster, finished supervisors are kept in the registry.
+// A zero value keeps finished supervisors forever.
+type Retention struct {
+	MaxAge        int `json:"max-age,omitempty"`         // minutes a supervisor is kept after it finished
+	MaxPerCluster int `json:"max-per-cluster,omitempty"` // finished supervisors kept for each cluster, newest first
+}
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 500
+	Unlimited       = -1 // a limit that returns every supervisor the filter selects in one page
+)
+
+// Filter
+// Selects supervisors by any combination of its fields, an id is preferred over the
+// module and cluster. A zero field, or time, does not restrict the supervisors selected.
 type Filter struct {
 	Module  string
 	Cluster string
 	Id      uint64
+
+	Status         []Status // any of the statuses
+	Processor      string
+	CreatedAfter   time.Time
+	CreatedBefore  time.Time
+	FinishedAfter  time.Time
+	FinishedBefore time.Time
+	Metadata       string // a metadata key, or key=value to also match the value
+	// the labels of the supervisor must match, ex. team=billing,env!=dev
+	Selector interfaces.Selector
+
+	Limit  int    // supervisors in a page, DefaultPageSize if zero or every supervisor if Unlimited
+	Cursor string // the next cursor of the previous page
+}
+
+// Page
+// The supervisors selected by a filter, newest first. Next is passed as the cursor of
+// the filter to get the following page, and is empty on the last page.
+type Page struct {
+	Supervisors []*Supervisor `json:"supervisors"`
+	Next        string        `json:"next,omitempty"`
 }
 
 type Registry struct {
 	supervisors map[uint64]*Supervisor
+	Broker      *Broker
 
 	counter uint64
 	mutex   sync.RWMutex
@@ -78,6 +226,7 @@ func NewRegistry() *Registry {
 
 	registry := new(Registry)
 	registry.supervisors = make(map[uint64]*Supervisor)
+	registry.Broker = NewBroker()
 	registry.counter = 1
 	return registry
 }
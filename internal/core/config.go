@@ -67,6 +67,18 @@ type Config struct {
 		ProbeEvery uint32 `yaml:"probe-every"`
 		MaxRetry   uint32 `yaml:"max-retry"`
 	} `yaml:"processor"`
+	Supervisor struct {
+		CancelTimeout    float64 `yaml:"cancel-timeout"`
+		HeartbeatTimeout float64 `yaml:"heartbeat-timeout"`
+		Retention        struct {
+			MaxAge        int     `yaml:"max-age"`         // minutes a finished supervisor is kept, 0 to keep forever
+			MaxPerCluster int     `yaml:"max-per-cluster"` // finished supervisors kept for each cluster, 0 for no limit
+			CollectEvery  float64 `yaml:"collect-every"`   // seconds between evictions
+			Archive       bool    `yaml:"archive"`         // archive evicted supervisors instead of deleting them
+		} `yaml:"retention"`
+
+		IdempotencyWindow float64 `yaml:"idempotency-window"` // minutes a supervisor is found again by its idempotency key
+	} `yaml:"supervisor"`
 	Path string
 }
 
@@ -89,6 +101,11 @@ func NewConfig(name string) *Config {
 	config.Processor.ProbeEvery = 10
 	config.Processor.MaxRetry = 5
 
+	config.Supervisor.CancelTimeout = 30
+	config.Supervisor.IdempotencyWindow = 1440
+	config.Supervisor.Retention.CollectEvery = 60
+	config.Supervisor.Retention.Archive = true
+
 	config.Net.Client.Port = 8136        // default
 	config.Net.Client.Host = "localhost" // default
 
@@ -156,6 +173,7 @@ func (config *Config) FillHttpProcessorConfig(processorClientConfig *http_proces
 	processorClientConfig.Net.Host = config.Net.Processor.Host
 	processorClientConfig.Net.Port = config.Net.Processor.Port
 	processorClientConfig.Timeout = config.MaxWaitForResponse
+	processorClientConfig.ResultExpiry = config.Cache.Expiry
 }
 
 func (config *Config) FillMessengerConfig(messengerConfig *messenger.Config) {
@@ -189,6 +207,13 @@ func (config *Config) FillSupervisorConfig(supervisorConfig *supervisor.Config)
 	// TODO - add panic check
 	supervisorConfig.Debug = config.Debug
 	supervisorConfig.Timeout = config.MaxWaitForResponse
+	supervisorConfig.CancelTimeout = config.Supervisor.CancelTimeout
+	supervisorConfig.HeartbeatTimeout = config.Supervisor.HeartbeatTimeout
+	supervisorConfig.IdempotencyWindow = config.Supervisor.IdempotencyWindow
+	supervisorConfig.Retention.MaxAge = config.Supervisor.Retention.MaxAge
+	supervisorConfig.Retention.MaxPerCluster = config.Supervisor.Retention.MaxPerCluster
+	supervisorConfig.CollectEvery = config.Supervisor.Retention.CollectEvery
+	supervisorConfig.Archive = config.Supervisor.Retention.Archive
 }
 
 func (config *Config) FillSchedulerConfig(schedulerConfig *scheduler.Config) {
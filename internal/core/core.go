@@ -53,6 +53,10 @@ type Core struct {
 	C25       chan common.ThreadResponse // CacheResponse
 	C26       chan common.ThreadRequest  // CacheRequest
 	C27       chan common.ThreadResponse // CacheResponse
+	C28       chan common.ThreadRequest  // SchedulerRequest
+	C29       chan common.ThreadResponse // SchedulerResponse
+	C30       chan common.ThreadRequest  // ProcessorRequest
+	C31       chan common.ThreadResponse // ProcessorResponse
 	interrupt chan common.InterruptEvent // InterruptEvent
 
 	config *Config
@@ -90,6 +94,10 @@ func New(configPath string) (*Core, error) {
 	core.C25 = make(chan common.ThreadResponse, 10)
 	core.C26 = make(chan common.ThreadRequest, 10)
 	core.C27 = make(chan common.ThreadResponse, 10)
+	core.C28 = make(chan common.ThreadRequest, 10)
+	core.C29 = make(chan common.ThreadResponse, 10)
+	core.C30 = make(chan common.ThreadRequest, 10)
+	core.C31 = make(chan common.ThreadResponse, 10)
 
 	/* load the cfg in for the first time */
 	core.config = GetConfigInstance(configPath)
@@ -125,7 +133,8 @@ func New(configPath string) (*Core, error) {
 	processorConfig := &processor.Config{}
 	core.config.FillProcessorConfig(processorConfig)
 	core.ProcessorThread, err = processor.New(processorConfig, processorLogger,
-		core.interrupt, core.C5, core.C6, core.C7, core.C8, core.C11, core.C12, core.C13, core.C14, core.C18, core.C19)
+		core.interrupt, core.C5, core.C6, core.C7, core.C8, core.C11, core.C12, core.C13, core.C14, core.C18, core.C19,
+		core.C30, core.C31)
 	if err != nil {
 		return nil, err
 	}
@@ -137,7 +146,8 @@ func New(configPath string) (*Core, error) {
 	supervisorConfig := &supervisor.Config{}
 	core.config.FillSupervisorConfig(supervisorConfig)
 	core.SupervisorThread, err = supervisor.NewThread(supervisorConfig, supervisorLogger,
-		core.interrupt, core.C13, core.C14, core.C15, core.C16, core.C17)
+		core.interrupt, core.C13, core.C14, core.C15, core.C16, core.C17, core.C28, core.C29,
+		core.C30, core.C31)
 	if err != nil {
 		return nil, err
 	}
@@ -186,7 +196,7 @@ func New(configPath string) (*Core, error) {
 	schedulerConig := &scheduler.Config{}
 	core.config.FillSchedulerConfig(schedulerConig)
 	core.SchedulerThread, err = scheduler.New(schedulerConig, schedulerLogger,
-		core.interrupt, core.C18, core.C19, core.C20, core.C21, core.C26, core.C27)
+		core.interrupt, core.C18, core.C19, core.C20, core.C21, core.C26, core.C27, core.C28, core.C29)
 	if err != nil {
 		return nil, err
 	}
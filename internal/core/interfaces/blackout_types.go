@@ -0,0 +1,131 @@
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	InvalidBlackoutWindow  = errors.New("blackout window must have a name and either a start and end or a schedule and duration")
+	InvalidBlackoutScope   = errors.New("blackout window can not be scoped to a module and a job at once")
+	BlackoutWindowNotFound = errors.New("blackout window does not exist")
+)
+
+// Blackout
+// A named window of time where scheduled jobs must not fire. A window is either
+// one-off, between Start and End, or recurring, opening each time the Schedule
+// fires and staying open for Duration minutes. A window without a module or
+// job applies to every job.
+type Blackout struct {
+	Name     string    `yaml:"name" json:"name" bson:"name"`
+	Module   string    `yaml:"module,omitempty" json:"module,omitempty" bson:"module,omitempty"` // applies to every job of the module
+	Job      string    `yaml:"job,omitempty" json:"job,omitempty" bson:"job,omitempty"`          // applies to the job with the identifier
+	Start    time.Time `yaml:"start,omitempty" json:"start,omitempty" bson:"start,omitempty"`
+	End      time.Time `yaml:"end,omitempty" json:"end,omitempty" bson:"end,omitempty"`
+	Schedule string    `yaml:"schedule,omitempty" json:"schedule,omitempty" bson:"schedule,omitempty"` // crontab expression the window opens on
+	Duration int       `yaml:"duration,omitempty" json:"duration,omitempty" bson:"duration,omitempty"` // minutes a recurring window stays open
+	Timezone string    `yaml:"timezone,omitempty" json:"timezone,omitempty" bson:"timezone,omitempty"` // IANA timezone the schedule is evaluated in
+}
+
+// IsRecurring
+// Returns true if the window opens on a crontab schedule.
+func (blackout Blackout) IsRecurring() bool {
+	return blackout.Schedule != ""
+}
+
+func (blackout Blackout) Validate() error {
+
+	if blackout.Name == "" {
+		return InvalidBlackoutWindow
+	}
+
+	if (blackout.Module != "") && (blackout.Job != "") {
+		return InvalidBlackoutScope
+	}
+
+	if !blackout.IsRecurring() {
+		if blackout.Start.IsZero() || !blackout.End.After(blackout.Start) {
+			return InvalidBlackoutWindow
+		}
+		return nil
+	}
+
+	if blackout.Duration <= 0 {
+		return InvalidBlackoutWindow
+	}
+
+	if _, err := ParseCron(blackout.Schedule); err != nil {
+		return err
+	}
+
+	if _, err := time.LoadLocation(blackout.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %s", blackout.Timezone)
+	}
+
+	return nil
+}
+
+// Covers
+// Returns true if the window applies to the job.
+func (blackout Blackout) Covers(job *Job) bool {
+
+	if blackout.Job != "" {
+		return blackout.Job == job.Identifier
+	}
+
+	if blackout.Module != "" {
+		return blackout.Module == job.Module
+	}
+
+	return true
+}
+
+// Until
+// Returns true if the window is open at the time t and the time the window closes.
+func (blackout Blackout) Until(t time.Time) (open bool, end time.Time) {
+
+	if !blackout.IsRecurring() {
+		if t.Before(blackout.Start) || !t.Before(blackout.End) {
+			return false, end
+		}
+		return true, blackout.End
+	}
+
+	cron, err := ParseCron(blackout.Schedule)
+	if err != nil {
+		return false, end
+	}
+
+	loc, err := time.LoadLocation(blackout.Timezone)
+	if err != nil {
+		return false, end
+	}
+
+	duration := time.Duration(blackout.Duration) * time.Minute
+
+	// the window is open if it was opened within the duration before t, the
+	// latest opening is used in case a recurrence overlaps the previous one
+	for next := cron.Next(t.In(loc).Add(-duration)); !next.IsZero() && !next.After(t); next = cron.Next(next) {
+		open, end = true, next.Add(duration)
+	}
+
+	return open, end
+}
+
+func (blackout Blackout) ToString() string {
+
+	scope := "all jobs"
+	if blackout.Job != "" {
+		scope = "job " + blackout.Job
+	} else if blackout.Module != "" {
+		scope = "module " + blackout.Module
+	}
+
+	if blackout.IsRecurring() {
+		return fmt.Sprintf("%s (%s) on %s for %dm", blackout.Name, scope, blackout.Schedule, blackout.Duration)
+	}
+
+	return fmt.Sprintf("%s (%s) from %s to %s", blackout.Name, scope,
+		blackout.Start.Format(time.RFC3339), blackout.End.Format(time.RFC3339))
+}
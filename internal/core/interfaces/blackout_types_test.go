@@ -0,0 +1,48 @@
+package interfaces
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBlackout_Until(t *testing.T) {
+
+	start := time.Date(2024, time.March, 31, 18, 0, 0, 0, time.UTC)
+	blackout := Blackout{Name: "month-end", Start: start, End: start.Add(6 * time.Hour)}
+
+	if err := blackout.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	if open, end := blackout.Until(start.Add(time.Hour)); !open || !end.Equal(blackout.End) {
+		t.Error("expected the window to be open until its end")
+	}
+
+	if open, _ := blackout.Until(blackout.End); open {
+		t.Error("expected the window to be closed at its end")
+	}
+}
+
+func TestBlackout_Until2(t *testing.T) {
+
+	// database maintenance every sunday at 01:00 for two hours
+	blackout := Blackout{Name: "maintenance", Module: "common", Schedule: "0 1 * * sun", Duration: 120, Timezone: "UTC"}
+
+	if err := blackout.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	sunday := time.Date(2024, time.March, 3, 2, 30, 0, 0, time.UTC)
+	open, end := blackout.Until(sunday)
+	if !open || !end.Equal(time.Date(2024, time.March, 3, 3, 0, 0, 0, time.UTC)) {
+		t.Error("expected the window to be open for two hours after it opens")
+	}
+
+	if open, _ := blackout.Until(sunday.Add(24 * time.Hour)); open {
+		t.Error("expected the window to be closed on monday")
+	}
+
+	if !blackout.Covers(&Job{Module: "common"}) || blackout.Covers(&Job{Module: "other"}) {
+		t.Error("expected the window to only cover jobs of its module")
+	}
+}
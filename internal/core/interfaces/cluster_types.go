@@ -43,6 +43,49 @@ type Config struct {
 	ETChannelGrowthFactor       int     `json:"et-channel-growth-factor"`
 	TLChannelThreshold          int     `json:"tl-channel-threshold"`
 	TLChannelGrowthFactor       int     `json:"tl-channel-growth-factor"`
+	MaxRuntime                  int     `json:"max-runtime,omitempty"`     // seconds a supervisor can run before it is crashed, 0 for no limit
+	MaxRestarts                 int     `json:"max-restarts,omitempty"`    // times a crashed supervisor is restarted when on-crash is Restart
+	RestartDelay                int     `json:"restart-delay,omitempty"`   // seconds before the first restart, doubled on every attempt
+	MaxConcurrency              int     `json:"max-concurrency,omitempty"` // supervisors of the cluster that can run at once, 0 for no limit
+}
+
+const (
+	DefaultMaxRestarts  = 3
+	DefaultRestartDelay = 5   // seconds
+	MaxRestartDelay     = 300 // seconds
+)
+
+// RestartBackoff
+// Returns how long to wait before the given restart attempt, counting from 1, and false
+// if the cluster does not restart crashed supervisors or the attempts are exhausted.
+func (config Config) RestartBackoff(attempt int) (time.Duration, bool) {
+
+	if config.OnCrash != Restart {
+		return 0, false
+	}
+
+	limit := config.MaxRestarts
+	if limit <= 0 {
+		limit = DefaultMaxRestarts
+	}
+
+	if (attempt <= 0) || (attempt > limit) {
+		return 0, false
+	}
+
+	delay := config.RestartDelay
+	if delay <= 0 {
+		delay = DefaultRestartDelay
+	}
+
+	for i := 1; (i < attempt) && (delay < MaxRestartDelay); i++ {
+		delay *= 2
+	}
+	if delay > MaxRestartDelay {
+		delay = MaxRestartDelay
+	}
+
+	return time.Duration(delay) * time.Second, true
 }
 
 type Status uint8
@@ -0,0 +1,38 @@
+package interfaces
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfig_RestartBackoff(t *testing.T) {
+
+	config := Config{OnCrash: Restart, MaxRestarts: 4, RestartDelay: 100}
+
+	expected := []time.Duration{100 * time.Second, 200 * time.Second, 300 * time.Second, 300 * time.Second}
+	for idx, delay := range expected {
+		if actual, ok := config.RestartBackoff(idx + 1); !ok || (actual != delay) {
+			t.Errorf("expected restart %d to wait %s but got %s", idx+1, delay, actual)
+		}
+	}
+
+	if _, ok := config.RestartBackoff(5); ok {
+		t.Error("expected the restarts to be exhausted after the max")
+	}
+}
+
+func TestConfig_RestartBackoff2(t *testing.T) {
+
+	if _, ok := (Config{OnCrash: DoNothing}).RestartBackoff(1); ok {
+		t.Error("expected a cluster that does nothing on crash to not be restarted")
+	}
+
+	config := Config{OnCrash: Restart}
+	if delay, ok := config.RestartBackoff(1); !ok || (delay != DefaultRestartDelay*time.Second) {
+		t.Error("expected the default delay to be used")
+	}
+
+	if _, ok := config.RestartBackoff(DefaultMaxRestarts + 1); ok {
+		t.Error("expected the default max restarts to be used")
+	}
+}
@@ -0,0 +1,294 @@
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var InvalidCronExpression = errors.New("invalid cron expression")
+
+// cronMacros
+// The non-standard shorthands supported by most cron implementations.
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronDayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// cronSearchLimit
+// The furthest into the future Next will look before giving up on an
+// expression that can never fire (ex. the 30th of February).
+const cronSearchLimit = 5
+
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	minuteField = cronField{name: "minute", min: 0, max: 59}
+	hourField   = cronField{name: "hour", min: 0, max: 23}
+	domField    = cronField{name: "day-of-month", min: 1, max: 31}
+	monthField  = cronField{name: "month", min: 1, max: 12, names: cronMonthNames}
+	dowField    = cronField{name: "day-of-week", min: 0, max: 7, names: cronDayNames}
+)
+
+// Cron
+// A parsed 5-field crontab expression (minute hour day-of-month month day-of-week).
+// Each field is stored as a bitset of the values it matches.
+type Cron struct {
+	expression string
+	minute     uint64
+	hour       uint64
+	dom        uint64
+	month      uint64
+	dow        uint64
+	domStar    bool // the day-of-month was left unrestricted
+	dowStar    bool // the day-of-week was left unrestricted
+}
+
+// ParseCron
+// Turns a crontab expression into a Cron. Fields support lists (1,2),
+// ranges (1-5), steps (*/2, 1-30/5), month and weekday names, and the
+// @yearly, @monthly, @weekly, @daily and @hourly macros.
+func ParseCron(expression string) (*Cron, error) {
+
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return nil, fmt.Errorf("%w: expression is empty", InvalidCronExpression)
+	}
+
+	expanded := expression
+	if strings.HasPrefix(expression, "@") {
+		macro, found := cronMacros[strings.ToLower(expression)]
+		if !found {
+			return nil, fmt.Errorf("%w: unknown macro %s", InvalidCronExpression, expression)
+		}
+		expanded = macro
+	}
+
+	fields := strings.Fields(expanded)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: expected 5 fields but found %d", InvalidCronExpression, len(fields))
+	}
+
+	cron := &Cron{expression: expression}
+
+	var err error
+	if cron.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if cron.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if cron.dom, err = domField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if cron.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if cron.dow, err = dowField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+
+	// crontab treats 7 as an alias of sunday
+	if cron.dow&(1<<7) != 0 {
+		cron.dow = (cron.dow | 1) &^ (1 << 7)
+	}
+
+	cron.domStar = isWildcard(fields[2])
+	cron.dowStar = isWildcard(fields[4])
+
+	return cron, nil
+}
+
+func isWildcard(field string) bool {
+	return field == "*" || field == "?"
+}
+
+func (field cronField) value(s string) (int, error) {
+
+	if n, found := field.names[strings.ToLower(s)]; found {
+		return n, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s is not a valid %s", InvalidCronExpression, s, field.name)
+	}
+
+	if (n < field.min) || (n > field.max) {
+		return 0, fmt.Errorf("%w: %s must be between %d and %d", InvalidCronExpression,
+			field.name, field.min, field.max)
+	}
+
+	return n, nil
+}
+
+func (field cronField) parse(s string) (uint64, error) {
+
+	var bits uint64
+
+	for _, part := range strings.Split(s, ",") {
+
+		if part == "" {
+			return 0, fmt.Errorf("%w: empty list entry in %s", InvalidCronExpression, field.name)
+		}
+
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if (err != nil) || (n <= 0) {
+				return 0, fmt.Errorf("%w: %s is not a valid step for %s", InvalidCronExpression, stepPart, field.name)
+			}
+			step = n
+		}
+
+		var start, end int
+		if isWildcard(rangePart) {
+			start, end = field.min, field.max
+		} else if lo, hi, isRange := strings.Cut(rangePart, "-"); isRange {
+			var err error
+			if start, err = field.value(lo); err != nil {
+				return 0, err
+			}
+			if end, err = field.value(hi); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("%w: %s range %s is backwards", InvalidCronExpression, field.name, rangePart)
+			}
+		} else {
+			var err error
+			if start, err = field.value(rangePart); err != nil {
+				return 0, err
+			}
+			end = start
+			// 'n/step' is shorthand for 'n-max/step'
+			if hasStep {
+				end = field.max
+			}
+		}
+
+		for i := start; i <= end; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+
+	return bits, nil
+}
+
+func has(bits uint64, value int) bool {
+	return bits&(1<<uint(value)) != 0
+}
+
+func (cron *Cron) dayMatches(t time.Time) bool {
+
+	domMatch := has(cron.dom, t.Day())
+	dowMatch := has(cron.dow, int(t.Weekday()))
+
+	// following crontab, when both day fields are restricted the job
+	// runs if either of them matches, otherwise both need to match
+	if !cron.domStar && !cron.dowStar {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+// Matches
+// Returns true if the expression fires during the minute containing t.
+func (cron *Cron) Matches(t time.Time) bool {
+
+	return has(cron.minute, t.Minute()) &&
+		has(cron.hour, t.Hour()) &&
+		has(cron.month, int(t.Month())) &&
+		cron.dayMatches(t)
+}
+
+// Next
+// Returns the first minute strictly after t the expression fires on, in the
+// location of t. A zero time is returned if the expression never fires.
+func (cron *Cron) Next(t time.Time) time.Time {
+
+	loc := t.Location()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	limit := day.AddDate(cronSearchLimit, 0, 0)
+
+	for ; day.Before(limit); day = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc) {
+
+		if !has(cron.month, int(day.Month())) || !cron.dayMatches(day) {
+			continue
+		}
+
+		if next, found := cron.nextOnDay(day, t); found {
+			return next
+		}
+	}
+
+	return time.Time{}
+}
+
+// nextOnDay
+// Returns the earliest time on the day after t the expression fires on. The search
+// walks the wall clock, so a daylight-saving transition can not skip or repeat a run;
+// a time inside a spring-forward gap is moved forward by the length of the gap, and
+// a time repeated by a fall-back transition is only visited once.
+func (cron *Cron) nextOnDay(day, t time.Time) (next time.Time, found bool) {
+
+	for hour := 0; hour < 24; hour++ {
+
+		if !has(cron.hour, hour) {
+			continue
+		}
+
+		for minute := 0; minute < 60; minute++ {
+
+			if !has(cron.minute, minute) {
+				continue
+			}
+
+			candidate := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+
+			// time.Date normalizes a time inside a gap using the offset from before
+			// the transition, which lands before the gap instead of after it
+			if (candidate.Hour() != hour) || (candidate.Minute() != minute) {
+				wanted := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, time.UTC)
+				got := time.Date(candidate.Year(), candidate.Month(), candidate.Day(),
+					candidate.Hour(), candidate.Minute(), 0, 0, time.UTC)
+				candidate = candidate.Add(wanted.Sub(got))
+			}
+
+			// times moved out of a gap can land after later times on the same day,
+			// so the whole day is searched for the earliest candidate
+			if candidate.After(t) && (!found || candidate.Before(next)) {
+				next, found = candidate, true
+			}
+		}
+	}
+
+	return next, found
+}
+
+func (cron *Cron) ToString() string {
+	return cron.expression
+}
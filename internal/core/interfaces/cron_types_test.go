@@ -0,0 +1,177 @@
+package interfaces
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+
+	valid := []string{
+		"* * * * *",
+		"*/15 * * * *",
+		"0 2 * * *",
+		"0,30 9-17 * * mon-fri",
+		"5 0 1-31/2 jan,jul *",
+		"0 0 * * 7",
+		"@daily",
+		"@HOURLY",
+	}
+
+	for _, expression := range valid {
+		if _, err := ParseCron(expression); err != nil {
+			t.Errorf("expected %s to be valid: %s", expression, err.Error())
+		}
+	}
+}
+
+func TestParseCron2(t *testing.T) {
+
+	invalid := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"1,,2 * * * *",
+		"@fortnightly",
+	}
+
+	for _, expression := range invalid {
+		if _, err := ParseCron(expression); !errors.Is(err, InvalidCronExpression) {
+			t.Errorf("expected %s to be rejected", expression)
+		}
+	}
+}
+
+func TestCron_Matches(t *testing.T) {
+
+	cron, _ := ParseCron("30 2 * * *")
+
+	if !cron.Matches(time.Date(2024, time.March, 4, 2, 30, 0, 0, time.UTC)) {
+		t.Error("expected the cron to match 02:30")
+	}
+
+	if cron.Matches(time.Date(2024, time.March, 4, 3, 30, 0, 0, time.UTC)) {
+		t.Error("expected the cron not to match 03:30")
+	}
+}
+
+func TestCron_Matches2(t *testing.T) {
+
+	// when both day fields are restricted either may match
+	cron, _ := ParseCron("0 0 13 * fri")
+
+	// wednesday the 13th
+	if !cron.Matches(time.Date(2024, time.March, 13, 0, 0, 0, 0, time.UTC)) {
+		t.Error("expected the day-of-month to match")
+	}
+
+	// friday the 15th
+	if !cron.Matches(time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Error("expected the day-of-week to match")
+	}
+
+	// thursday the 14th
+	if cron.Matches(time.Date(2024, time.March, 14, 0, 0, 0, 0, time.UTC)) {
+		t.Error("expected neither day field to match")
+	}
+}
+
+func TestCron_Next(t *testing.T) {
+
+	cron, _ := ParseCron("@weekly")
+
+	// monday
+	now := time.Date(2024, time.March, 4, 12, 0, 0, 0, time.UTC)
+	next := cron.Next(now)
+
+	expected := time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC)
+	if !next.Equal(expected) {
+		t.Errorf("expected next run at %s but got %s", expected, next)
+	}
+}
+
+func TestCron_Next2(t *testing.T) {
+
+	cron, _ := ParseCron("0 0 30 feb *")
+
+	if next := cron.Next(time.Now()); !next.IsZero() {
+		t.Error("expected a cron that never fires to return a zero time")
+	}
+}
+
+func TestJob_Expression(t *testing.T) {
+
+	job := Job{Interval: Interval{Minute: 2}}
+	if expression := job.Expression(); expression != "*/2 * * * *" {
+		t.Errorf("expected the interval to be translated to crontab form, got %s", expression)
+	}
+
+	job.Schedule = "@daily"
+	if expression := job.Expression(); expression != "@daily" {
+		t.Error("expected the schedule to take precedence over the interval")
+	}
+}
+
+func TestCron_Next3(t *testing.T) {
+
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("timezone database is not available")
+	}
+
+	// 02:30 does not exist on the 10th when the clocks spring forward
+	cron, _ := ParseCron("30 2 * * *")
+
+	next := cron.Next(time.Date(2024, time.March, 9, 12, 0, 0, 0, loc))
+	expected := time.Date(2024, time.March, 10, 3, 30, 0, 0, loc)
+	if !next.Equal(expected) {
+		t.Errorf("expected the run in the gap to move to %s but got %s", expected, next)
+	}
+
+	next = cron.Next(next)
+	expected = time.Date(2024, time.March, 11, 2, 30, 0, 0, loc)
+	if !next.Equal(expected) {
+		t.Errorf("expected the following run at %s but got %s", expected, next)
+	}
+}
+
+func TestCron_Next4(t *testing.T) {
+
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("timezone database is not available")
+	}
+
+	// 01:30 happens twice on the 3rd when the clocks fall back
+	cron, _ := ParseCron("30 1 * * *")
+
+	runs := 0
+	for next := cron.Next(time.Date(2024, time.November, 3, 0, 0, 0, 0, loc)); next.Day() == 3; next = cron.Next(next) {
+		runs++
+	}
+
+	if runs != 1 {
+		t.Errorf("expected a single run on the day the clocks fall back but got %d", runs)
+	}
+}
+
+func TestJob_Location(t *testing.T) {
+
+	job := Job{Identifier: "test", Module: "common", Cluster: "vec", Schedule: "@daily", Timezone: "Mars/Olympus_Mons"}
+	if err := job.Validate(); err == nil {
+		t.Error("expected an unknown timezone to be rejected")
+	}
+
+	job.Timezone = "Europe/London"
+	if err := job.Validate(); err != nil {
+		t.Error(err)
+	}
+}
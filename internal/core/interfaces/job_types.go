@@ -1,6 +1,7 @@
 package interfaces
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -13,7 +14,8 @@ const (
 // Dump
 // A static representation of the jobs in the scheduler
 type Dump struct {
-	Jobs []Job `yaml:"jobs"`
+	Jobs      []Job      `yaml:"jobs"`
+	Blackouts []Blackout `yaml:"blackouts,omitempty"`
 }
 
 // Interval
@@ -40,6 +42,30 @@ func (interval Interval) Empty() bool {
 	return (interval.Month == 0) && (interval.Day == 0) && (interval.Hour == 0) && (interval.Minute == 0)
 }
 
+// Validate
+// Returns an error if a field of the interval is negative or larger than the
+// unit it repeats in, ex. running every 90th minute.
+func (interval Interval) Validate() error {
+
+	if (interval.Minute < 0) || (interval.Minute > 60) {
+		return fmt.Errorf("interval minute %d must be between 0 and 60", interval.Minute)
+	}
+
+	if (interval.Hour < 0) || (interval.Hour > 23) {
+		return fmt.Errorf("interval hour %d must be between 0 and 23", interval.Hour)
+	}
+
+	if (interval.Day < 0) || (interval.Day > 31) {
+		return fmt.Errorf("interval day %d must be between 0 and 31", interval.Day)
+	}
+
+	if (interval.Month < 0) || (interval.Month > 12) {
+		return fmt.Errorf("interval month %d must be between 0 and 12", interval.Month)
+	}
+
+	return nil
+}
+
 func (interval Interval) Equals(other *Interval) bool {
 
 	if other == nil {
@@ -64,39 +90,355 @@ func (interval Interval) ToString() string {
 	return sb.String()
 }
 
+// MisfirePolicy
+// Decides what happens to runs of a job that were missed while the core was down.
+type MisfirePolicy string
+
+const (
+	MisfireSkip    MisfirePolicy = "skip"     // drop every missed run (default)
+	MisfireRunOnce MisfirePolicy = "run-once" // coalesce the missed runs into a single run
+	MisfireRunAll  MisfirePolicy = "run-all"  // run every missed slot, up to the misfire limit
+)
+
+const (
+	DefaultMisfireLimit = 10
+)
+
+// Misfire
+// Contains information about how a job should catch up on missed runs.
+type Misfire struct {
+	Policy MisfirePolicy `yaml:"policy,omitempty" json:"policy,omitempty" bson:"policy,omitempty"`
+	Limit  int           `yaml:"limit,omitempty" json:"limit,omitempty" bson:"limit,omitempty"` // max missed runs caught up by run-all
+}
+
+func (misfire Misfire) GetPolicy() MisfirePolicy {
+
+	if misfire.Policy == "" {
+		return MisfireSkip
+	}
+	return misfire.Policy
+}
+
+func (misfire Misfire) GetLimit() int {
+
+	if misfire.Limit <= 0 {
+		return DefaultMisfireLimit
+	}
+	return misfire.Limit
+}
+
+func (misfire Misfire) Validate() error {
+
+	switch misfire.GetPolicy() {
+	case MisfireSkip, MisfireRunOnce, MisfireRunAll:
+		return nil
+	default:
+		return fmt.Errorf("unknown misfire policy %s", misfire.Policy)
+	}
+}
+
+const (
+	DefaultRetryDelay      = 30.0  // seconds
+	DefaultRetryMultiplier = 2.0   // the delay grows by this factor each attempt
+	DefaultRetryMaxDelay   = 600.0 // seconds
+)
+
+// Retry
+// Contains information about how a job that failed to be provisioned is retried.
+// A job without a retry policy is only attempted once.
+type Retry struct {
+	MaxAttempts  int     `yaml:"max-attempts,omitempty" json:"max-attempts,omitempty" bson:"max-attempts,omitempty"`
+	InitialDelay float64 `yaml:"initial-delay,omitempty" json:"initial-delay,omitempty" bson:"initial-delay,omitempty"` // seconds
+	Multiplier   float64 `yaml:"multiplier,omitempty" json:"multiplier,omitempty" bson:"multiplier,omitempty"`
+	MaxDelay     float64 `yaml:"max-delay,omitempty" json:"max-delay,omitempty" bson:"max-delay,omitempty"` // seconds
+}
+
+func (retry Retry) GetMaxAttempts() int {
+
+	if retry.MaxAttempts <= 0 {
+		return 1
+	}
+	return retry.MaxAttempts
+}
+
+// Delay
+// Returns how long to wait after the attempt failed before trying again.
+func (retry Retry) Delay(attempt int) time.Duration {
+
+	delay := retry.InitialDelay
+	if delay <= 0 {
+		delay = DefaultRetryDelay
+	}
+
+	multiplier := retry.Multiplier
+	if multiplier < 1 {
+		multiplier = DefaultRetryMultiplier
+	}
+
+	maxDelay := retry.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultRetryMaxDelay
+	}
+
+	for i := 1; (i < attempt) && (delay < maxDelay); i++ {
+		delay *= multiplier
+	}
+
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	return time.Duration(delay * float64(time.Second))
+}
+
+func (retry Retry) Validate() error {
+
+	if (retry.MaxAttempts < 0) || (retry.InitialDelay < 0) || (retry.MaxDelay < 0) {
+		return errors.New("retry policy can not be negative")
+	}
+
+	if (retry.Multiplier != 0) && (retry.Multiplier < 1) {
+		return errors.New("retry multiplier must be at least 1")
+	}
+
+	return nil
+}
+
+// UpstreamPolicy
+// Decides what happens to a dependent job when a job it depends on crashes.
+type UpstreamPolicy string
+
+const (
+	UpstreamSkip  UpstreamPolicy = "skip"  // silently skip the dependent run (default)
+	UpstreamAlert UpstreamPolicy = "alert" // skip the dependent run and raise a fatal log on the upstream supervisor
+)
+
+// ConcurrencyPolicy
+// Decides what happens when a job is due while a previous run of it is still active.
+type ConcurrencyPolicy string
+
+const (
+	ConcurrencyAllow   ConcurrencyPolicy = "allow"   // run both supervisors side by side (default)
+	ConcurrencyForbid  ConcurrencyPolicy = "forbid"  // skip the new run
+	ConcurrencyReplace ConcurrencyPolicy = "replace" // cancel the active supervisors and start the new run
+)
+
 // Job
 // Contains information about how often a module/cluster pair should
 // and what config should be used during that scheduled interval.
+// A cron expression in Schedule takes precedence over the legacy Interval.
+// Jobs that depend on other jobs without a schedule are only triggered when
+// every job they depend on completes.
 type Job struct {
-	Identifier       string            `yaml:"identifier" json:"identifier" bson:"identifier"`
-	Module           string            `yaml:"module" json:"module" bson:"module"`
-	Cluster          string            `yaml:"cluster" json:"cluster" bson:"cluster"`
-	Config           string            `yaml:"config" json:"config" bson:"config"`
-	Interval         Interval          `yaml:"interval" json:"interval" bson:"interval"`
-	Metadata         map[string]string `yaml:"metadata,omitempty" json:"metadata,omitempty" bson:"metadata,omitempty"`
-	lastAttemptedRun time.Time
-	running          bool
-}
-
-// IsJobRunning
-// returns if a job is currently marked as running.
-func IsJobRunning(job *Job) bool {
-	return job.running
-}
-
-// IsTimeToRun
-// Returns true if a job is ready to run based on the current time the function is called.
-// If the minute interval for the job is 2, we will run the job every minute that is divisible
-// by two. This is similar to the '*/2' notation used by cronjob.
-func IsTimeToRun(job *Job) bool {
-
-	// we shouldn't schedule a job twice in the same period
-	if job.running {
-		return false
+	Identifier      string            `yaml:"identifier" json:"identifier" bson:"identifier"`
+	Module          string            `yaml:"module" json:"module" bson:"module"`
+	Cluster         string            `yaml:"cluster" json:"cluster" bson:"cluster"`
+	Config          string            `yaml:"config" json:"config" bson:"config"`
+	Schedule        string            `yaml:"schedule,omitempty" json:"schedule,omitempty" bson:"schedule,omitempty"`
+	Timezone        string            `yaml:"timezone,omitempty" json:"timezone,omitempty" bson:"timezone,omitempty"` // IANA zone the schedule is evaluated in
+	Interval        Interval          `yaml:"interval,omitempty" json:"interval,omitempty" bson:"interval,omitempty"`
+	Metadata        map[string]string `yaml:"metadata,omitempty" json:"metadata,omitempty" bson:"metadata,omitempty"`
+	Labels          map[string]string `yaml:"labels,omitempty" json:"labels,omitempty" bson:"labels,omitempty"` // copied onto every supervisor the job provisions
+	Misfire         Misfire           `yaml:"misfire,omitempty" json:"misfire,omitempty" bson:"misfire,omitempty"`
+	LastFired       time.Time         `yaml:"last-fired,omitempty" json:"last-fired,omitempty" bson:"last-fired,omitempty"`
+	DependsOn       []string          `yaml:"depends-on,omitempty" json:"depends-on,omitempty" bson:"depends-on,omitempty"`
+	OnUpstreamCrash UpstreamPolicy    `yaml:"on-upstream-crash,omitempty" json:"on-upstream-crash,omitempty" bson:"on-upstream-crash,omitempty"`
+	Concurrency     ConcurrencyPolicy `yaml:"concurrency,omitempty" json:"concurrency,omitempty" bson:"concurrency,omitempty"`
+	Paused          bool              `yaml:"paused,omitempty" json:"paused,omitempty" bson:"paused,omitempty"`
+	Priority        int               `yaml:"priority,omitempty" json:"priority,omitempty" bson:"priority,omitempty"` // runs with a higher priority are admitted first when over a concurrency limit
+	Retry           Retry             `yaml:"retry,omitempty" json:"retry,omitempty" bson:"retry,omitempty"`
+	RunAt           time.Time         `yaml:"run-at,omitempty" json:"run-at,omitempty" bson:"run-at,omitempty"` // fire once at this time instead of repeating
+	Finished        bool              `yaml:"finished,omitempty" json:"finished,omitempty" bson:"finished,omitempty"`
+	Result          string            `yaml:"result,omitempty" json:"result,omitempty" bson:"result,omitempty"` // the final status of a run-at job
+}
+
+// Expression
+// Returns the crontab expression the job is scheduled on. Jobs that only
+// define an Interval are translated into the equivalent crontab form.
+func (job Job) Expression() string {
+
+	if job.Schedule != "" {
+		return job.Schedule
+	}
+
+	return job.Interval.ToString() + DefaultPlaceholder
+}
+
+// Cron
+// Parses the crontab expression the job is scheduled on.
+func (job Job) Cron() (*Cron, error) {
+
+	return ParseCron(job.Expression())
+}
+
+// Location
+// Returns the timezone the job's schedule is evaluated in, jobs without a
+// timezone use the local zone of the host.
+func (job Job) Location() (*time.Location, error) {
+
+	if job.Timezone == "" {
+		return time.Local, nil
+	}
+
+	loc, err := time.LoadLocation(job.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %s", job.Timezone)
+	}
+	return loc, nil
+}
+
+// Validate
+// Returns an error if the job is missing required fields or has a
+// schedule that can not be parsed.
+func (job Job) Validate() error {
+
+	if job.Identifier == "" {
+		return errors.New("job is missing an identifier")
+	}
+
+	if (job.Module == "") || (job.Cluster == "") {
+		return errors.New("job is missing a module or cluster")
+	}
+
+	// a job without any trigger would otherwise fall back to firing every minute
+	if (job.Schedule == "") && job.Interval.Empty() && !job.IsOneShot() && (len(job.DependsOn) == 0) {
+		return errors.New("job is missing a schedule, interval, run-at or depends-on")
+	}
+
+	if job.IsOneShot() {
+		if (job.Schedule != "") || !job.Interval.Empty() {
+			return errors.New("run-at job can not also have a schedule or interval")
+		}
+	} else if job.IsTimeTriggered() {
+		if job.Schedule == "" {
+			if err := job.Interval.Validate(); err != nil {
+				return err
+			}
+		}
+		if _, err := job.Cron(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := job.Location(); err != nil {
+		return err
+	}
+
+	// templates are rendered against a sample run so errors surface when the job is created
+	sample := MetadataContext{Job: job.Identifier, Module: job.Module, Cluster: job.Cluster, ScheduledTime: time.Now(), Attempt: 1}
+	if _, err := job.RenderMetadata(sample); err != nil {
+		return err
+	}
+
+	if err := ValidateLabels(job.Labels); err != nil {
+		return err
+	}
+
+	for _, upstream := range job.DependsOn {
+		if upstream == job.Identifier {
+			return errors.New("job can not depend on itself")
+		}
+	}
+
+	// a dependent that also fired on a schedule would run twice, once on its own
+	if (len(job.DependsOn) > 0) && ((job.Schedule != "") || !job.Interval.Empty() || job.IsOneShot()) {
+		return errors.New("job that depends on other jobs can not also have a schedule, interval or run-at")
+	}
+
+	switch job.GetUpstreamPolicy() {
+	case UpstreamSkip, UpstreamAlert:
+	default:
+		return fmt.Errorf("unknown upstream crash policy %s", job.OnUpstreamCrash)
+	}
+
+	switch job.GetConcurrencyPolicy() {
+	case ConcurrencyAllow, ConcurrencyForbid, ConcurrencyReplace:
+	default:
+		return fmt.Errorf("unknown concurrency policy %s", job.Concurrency)
+	}
+
+	if err := job.Retry.Validate(); err != nil {
+		return err
+	}
+
+	return job.Misfire.Validate()
+}
+
+// Upcoming
+// Returns up to count times after t the job will fire on, a job that only
+// depends on other jobs or a run-at job that already fired has none.
+func (job Job) Upcoming(t time.Time, count int) []time.Time {
+
+	upcoming := make([]time.Time, 0, count)
+
+	if job.IsOneShot() {
+		if !job.Finished && job.LastFired.IsZero() && job.RunAt.After(t) && (count > 0) {
+			upcoming = append(upcoming, job.RunAt)
+		}
+		return upcoming
+	}
+
+	if !job.IsTimeTriggered() {
+		return upcoming
+	}
+
+	cron, err := job.Cron()
+	if err != nil {
+		return upcoming
+	}
+
+	loc, err := job.Location()
+	if err != nil {
+		return upcoming
 	}
 
-	curr := time.Now().Minute()
-	return (curr % job.Interval.Minute) == 0
+	for next := cron.Next(t.In(loc)); !next.IsZero() && (len(upcoming) < count); next = cron.Next(next) {
+		upcoming = append(upcoming, next)
+	}
+
+	return upcoming
+}
+
+// IsTimeTriggered
+// Returns false if the job should only be triggered by the jobs it depends on.
+func (job Job) IsTimeTriggered() bool {
+
+	return (len(job.DependsOn) == 0) || (job.Schedule != "") || !job.Interval.Empty() || job.IsOneShot()
+}
+
+// IsOneShot
+// Returns true if the job fires once at its run-at time instead of repeating.
+func (job Job) IsOneShot() bool {
+
+	return !job.RunAt.IsZero()
+}
+
+// DependsOnJob
+// Returns true if the job has a depends-on edge to the job with the identifier.
+func (job Job) DependsOnJob(identifier string) bool {
+
+	for _, upstream := range job.DependsOn {
+		if upstream == identifier {
+			return true
+		}
+	}
+	return false
+}
+
+func (job Job) GetUpstreamPolicy() UpstreamPolicy {
+
+	if job.OnUpstreamCrash == "" {
+		return UpstreamSkip
+	}
+	return job.OnUpstreamCrash
+}
+
+func (job Job) GetConcurrencyPolicy() ConcurrencyPolicy {
+
+	if job.Concurrency == "" {
+		return ConcurrencyAllow
+	}
+	return job.Concurrency
 }
 
 func (job Job) Equals(other *Job) bool {
@@ -115,13 +457,34 @@ func (job Job) Equals(other *Job) bool {
 		return false
 	}
 
-	return job.Interval.Equals(&other.Interval)
+	return (job.Expression() == other.Expression()) &&
+		job.RunAt.Equal(other.RunAt) &&
+		(job.Timezone == other.Timezone) &&
+		(job.IsTimeTriggered() == other.IsTimeTriggered()) &&
+		(strings.Join(job.DependsOn, ",") == strings.Join(other.DependsOn, ","))
 }
 
 func (job Job) ToString() string {
 
-	return fmt.Sprintf("%s %s.%s (cluster: %s, config: %s)",
-		job.Interval.ToString(), job.Module, job.Identifier, job.Cluster, job.Config)
+	trigger := job.Expression()
+	if job.Timezone != "" {
+		trigger += " " + job.Timezone
+	}
+	if job.IsOneShot() {
+		trigger = "at " + job.RunAt.Format(time.RFC3339)
+	} else if !job.IsTimeTriggered() {
+		trigger = "after " + strings.Join(job.DependsOn, ",")
+	}
+
+	state := ""
+	if job.Finished {
+		state = fmt.Sprintf(" [finished: %s]", job.Result)
+	} else if job.Paused {
+		state = " [paused]"
+	}
+
+	return fmt.Sprintf("%s %s.%s (cluster: %s, config: %s)%s",
+		trigger, job.Module, job.Identifier, job.Cluster, job.Config, state)
 }
 
 // Filter
@@ -131,6 +494,7 @@ type Filter struct {
 	Module     string
 	Cluster    string
 	Interval   Interval
+	Selector   Selector // the labels of the job must match, on its own it searches every job
 }
 
 func (filter Filter) UseIdentifier() bool {
@@ -149,3 +513,7 @@ func (filter Filter) UseInterval() bool {
 
 	return !filter.Interval.Empty() && (filter.Module != "") && (filter.Cluster != "")
 }
+
+func (filter Filter) UseSelector() bool {
+	return !filter.Selector.Empty() && (filter.Identifier == "") && (filter.Module == "")
+}
@@ -0,0 +1,130 @@
+package interfaces
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetry_Delay(t *testing.T) {
+
+	retry := Retry{MaxAttempts: 5, InitialDelay: 10, Multiplier: 3, MaxDelay: 60}
+
+	expected := []time.Duration{10 * time.Second, 30 * time.Second, 60 * time.Second, 60 * time.Second}
+	for idx, delay := range expected {
+		if actual := retry.Delay(idx + 1); actual != delay {
+			t.Errorf("expected attempt %d to wait %s but got %s", idx+1, delay, actual)
+		}
+	}
+}
+
+func TestRetry_Delay2(t *testing.T) {
+
+	retry := Retry{}
+
+	if retry.GetMaxAttempts() != 1 {
+		t.Error("expected a job without a retry policy to be attempted once")
+	}
+
+	if retry.Delay(1) != time.Duration(DefaultRetryDelay*float64(time.Second)) {
+		t.Error("expected the default delay to be used")
+	}
+
+	if (Retry{Multiplier: 0.5}).Validate() == nil {
+		t.Error("expected a multiplier that shrinks the delay to be rejected")
+	}
+}
+
+func TestJob_IsOneShot(t *testing.T) {
+
+	job := Job{Identifier: "backfill", Module: "common", Cluster: "vec", RunAt: time.Now()}
+	if err := job.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	job.Schedule = "@daily"
+	if err := job.Validate(); err == nil {
+		t.Error("expected a run-at job with a schedule to be rejected")
+	}
+}
+
+func TestJob_Validate(t *testing.T) {
+
+	job := Job{Identifier: "nightly", Module: "common", Cluster: "vec"}
+	if err := job.Validate(); err == nil {
+		t.Error("expected a job without a trigger to be rejected")
+	}
+
+	job.DependsOn = []string{"extract"}
+	if err := job.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestJob_RenderMetadata(t *testing.T) {
+
+	job := Job{Metadata: map[string]string{
+		"date":    `{{date "2006-01-02" .ScheduledTime}}`,
+		"since":   `{{unix .PreviousRun}}`,
+		"attempt": `{{.Attempt}}`,
+		"region":  "ca-east",
+	}}
+
+	scheduled := time.Date(2024, time.March, 5, 2, 0, 0, 0, time.UTC)
+	ctx := MetadataContext{ScheduledTime: scheduled, PreviousRun: scheduled.Add(-24 * time.Hour), Attempt: 2}
+
+	rendered, err := job.RenderMetadata(ctx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if rendered["date"] != "2024-03-05" {
+		t.Error("expected the scheduled time to be rendered")
+	}
+
+	if rendered["since"] != "1709517600" {
+		t.Error("expected the previous run to be rendered")
+	}
+
+	if (rendered["attempt"] != "2") || (rendered["region"] != "ca-east") {
+		t.Error("expected the attempt to be rendered and plain values to be copied")
+	}
+}
+
+func TestJob_RenderMetadata2(t *testing.T) {
+
+	job := Job{Identifier: "nightly", Module: "common", Cluster: "vec", Schedule: "@daily",
+		Metadata: map[string]string{"date": "{{.Tomorrow}}"}}
+
+	if _, err := job.RenderMetadata(MetadataContext{}); !errors.Is(err, InvalidMetadataTemplate) {
+		t.Error("expected an unknown field to be rejected")
+	}
+
+	if err := job.Validate(); !errors.Is(err, InvalidMetadataTemplate) {
+		t.Error("expected a job with an invalid template to be rejected")
+	}
+}
+
+func TestJob_Upcoming(t *testing.T) {
+
+	job := Job{Identifier: "nightly", Module: "common", Cluster: "vec", Schedule: "0 2 * * *", Timezone: "UTC"}
+
+	from := time.Date(2024, time.March, 5, 3, 0, 0, 0, time.UTC)
+	upcoming := job.Upcoming(from, 3)
+	if len(upcoming) != 3 {
+		t.Error("expected three upcoming runs")
+		return
+	}
+
+	if !upcoming[0].Equal(time.Date(2024, time.March, 6, 2, 0, 0, 0, time.UTC)) ||
+		!upcoming[2].Equal(time.Date(2024, time.March, 8, 2, 0, 0, 0, time.UTC)) {
+		t.Error("expected the upcoming runs to be the next three nights")
+	}
+
+	job.Interval = Interval{Minute: 90}
+	job.Schedule = ""
+	if job.Validate() == nil {
+		t.Error("expected an interval of 90 minutes to be rejected")
+	}
+}
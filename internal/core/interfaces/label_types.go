@@ -0,0 +1,167 @@
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	InvalidLabel    = errors.New("invalid label")
+	InvalidSelector = errors.New("invalid label selector")
+)
+
+const (
+	MaxLabelLength = 63
+)
+
+// Operator
+// How a requirement of a selector compares the label with its key.
+type Operator string
+
+const (
+	Equals    Operator = "="
+	NotEquals Operator = "!="
+	Exists    Operator = "exists"
+	NotExists Operator = "!"
+)
+
+// Requirement
+// A single comparison of a selector, ex. team=billing, env!=dev, ticket or !ticket.
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Value    string
+}
+
+// Selector
+// A set of requirements the labels of a record must all meet, written as a comma separated
+// list such as "team=billing,env!=dev". An empty selector matches every record.
+type Selector []Requirement
+
+// validLabel
+// Returns true if the key or value is no longer than MaxLabelLength and only holds letters,
+// digits and the characters - _ . / so it can be written in a selector.
+func validLabel(s string) bool {
+
+	if len(s) > MaxLabelLength {
+		return false
+	}
+
+	for _, c := range s {
+		if !(('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') ||
+			(c == '-') || (c == '_') || (c == '.') || (c == '/')) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ValidateLabels
+// Returns an error if a key is empty or a key or value could not be written in a selector.
+func ValidateLabels(labels map[string]string) error {
+
+	for key, value := range labels {
+		if (key == "") || !validLabel(key) {
+			return fmt.Errorf("%w: key %q", InvalidLabel, key)
+		}
+		if !validLabel(value) {
+			return fmt.Errorf("%w: value %q of %s", InvalidLabel, value, key)
+		}
+	}
+
+	return nil
+}
+
+// ParseSelector
+// Parses a comma separated list of requirements, surrounding whitespace is ignored.
+func ParseSelector(s string) (Selector, error) {
+
+	selector := make(Selector, 0)
+	if strings.TrimSpace(s) == "" {
+		return selector, nil
+	}
+
+	for _, term := range strings.Split(s, ",") {
+
+		term = strings.TrimSpace(term)
+		requirement := Requirement{}
+
+		if key, value, found := strings.Cut(term, "!="); found {
+			requirement = Requirement{Key: key, Operator: NotEquals, Value: value}
+		} else if key, value, found = strings.Cut(term, "="); found {
+			requirement = Requirement{Key: key, Operator: Equals, Value: strings.TrimPrefix(value, "=")}
+		} else if key, found = strings.CutPrefix(term, "!"); found {
+			requirement = Requirement{Key: key, Operator: NotExists}
+		} else {
+			requirement = Requirement{Key: term, Operator: Exists}
+		}
+
+		requirement.Key = strings.TrimSpace(requirement.Key)
+		requirement.Value = strings.TrimSpace(requirement.Value)
+
+		if (requirement.Key == "") || !validLabel(requirement.Key) || !validLabel(requirement.Value) {
+			return nil, fmt.Errorf("%w: %q", InvalidSelector, term)
+		}
+
+		selector = append(selector, requirement)
+	}
+
+	return selector, nil
+}
+
+// Empty
+// Returns true if the selector has no requirements.
+func (selector Selector) Empty() bool {
+	return len(selector) == 0
+}
+
+// Matches
+// Returns true if the labels meet every requirement of the selector. A label that is
+// missing does not equal any value, so it always meets a != requirement.
+func (selector Selector) Matches(labels map[string]string) bool {
+
+	for _, requirement := range selector {
+
+		value, found := labels[requirement.Key]
+
+		switch requirement.Operator {
+		case Equals:
+			if !found || (value != requirement.Value) {
+				return false
+			}
+		case NotEquals:
+			if found && (value == requirement.Value) {
+				return false
+			}
+		case Exists:
+			if !found {
+				return false
+			}
+		case NotExists:
+			if found {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (selector Selector) ToString() string {
+
+	terms := make([]string, len(selector))
+	for idx, requirement := range selector {
+		switch requirement.Operator {
+		case Exists:
+			terms[idx] = requirement.Key
+		case NotExists:
+			terms[idx] = "!" + requirement.Key
+		default:
+			terms[idx] = requirement.Key + string(requirement.Operator) + requirement.Value
+		}
+	}
+
+	return strings.Join(terms, ",")
+}
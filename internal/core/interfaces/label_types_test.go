@@ -0,0 +1,68 @@
+package interfaces
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSelector(t *testing.T) {
+
+	selector, err := ParseSelector("team=billing, env!=dev,ticket,!canary")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := Selector{
+		{Key: "team", Operator: Equals, Value: "billing"},
+		{Key: "env", Operator: NotEquals, Value: "dev"},
+		{Key: "ticket", Operator: Exists},
+		{Key: "canary", Operator: NotExists},
+	}
+
+	if len(selector) != len(expected) {
+		t.Errorf("expected %d requirements but got %d", len(expected), len(selector))
+		return
+	}
+
+	for idx, requirement := range expected {
+		if selector[idx] != requirement {
+			t.Errorf("expected requirement %d to be %v but got %v", idx, requirement, selector[idx])
+		}
+	}
+
+	if selector.ToString() != "team=billing,env!=dev,ticket,!canary" {
+		t.Error("expected the selector to be written back the way it was parsed")
+	}
+
+	for _, invalid := range []string{"=billing", "team=bill ing", "team,,env", "!"} {
+		if _, err = ParseSelector(invalid); !errors.Is(err, InvalidSelector) {
+			t.Errorf("expected %q to be an invalid selector", invalid)
+		}
+	}
+}
+
+func TestSelector_Matches(t *testing.T) {
+
+	selector, _ := ParseSelector("team=billing,env!=dev")
+
+	if !selector.Matches(map[string]string{"team": "billing", "env": "prod"}) {
+		t.Error("expected the labels to match every requirement")
+	}
+
+	if !selector.Matches(map[string]string{"team": "billing"}) {
+		t.Error("expected a missing label to meet a != requirement")
+	}
+
+	if selector.Matches(map[string]string{"team": "billing", "env": "dev"}) {
+		t.Error("expected the labels to not match the != requirement")
+	}
+
+	if selector.Matches(nil) {
+		t.Error("expected a record without labels to not match an = requirement")
+	}
+
+	if empty, _ := ParseSelector(""); !empty.Empty() || !empty.Matches(nil) {
+		t.Error("expected an empty selector to match every record")
+	}
+}
@@ -0,0 +1,61 @@
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+var InvalidMetadataTemplate = errors.New("invalid metadata template")
+
+// MetadataContext
+// The values a job's metadata templates can reference when a run is provisioned,
+// ex. "{{.ScheduledTime.Format "2006-01-02"}}" or "{{date "2006-01-02" .PreviousRun}}".
+type MetadataContext struct {
+	Job           string
+	Module        string
+	Cluster       string
+	ScheduledTime time.Time // the time the run was due
+	PreviousRun   time.Time // the previous time the job was due, zero if it never was
+	Attempt       int
+}
+
+var metadataFuncs = template.FuncMap{
+	"date": func(layout string, t time.Time) string {
+		return t.Format(layout)
+	},
+	"unix": func(t time.Time) int64 {
+		return t.Unix()
+	},
+}
+
+// RenderMetadata
+// Executes each metadata value of the job as a template against the context.
+// Values without template actions are copied as they are.
+func (job Job) RenderMetadata(ctx MetadataContext) (map[string]string, error) {
+
+	rendered := make(map[string]string, len(job.Metadata))
+
+	for key, value := range job.Metadata {
+
+		if !strings.Contains(value, "{{") {
+			rendered[key] = value
+			continue
+		}
+
+		tmpl, err := template.New(key).Funcs(metadataFuncs).Option("missingkey=error").Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", InvalidMetadataTemplate, err.Error())
+		}
+
+		var sb strings.Builder
+		if err = tmpl.Execute(&sb, ctx); err != nil {
+			return nil, fmt.Errorf("%w: %s", InvalidMetadataTemplate, err.Error())
+		}
+		rendered[key] = sb.String()
+	}
+
+	return rendered, nil
+}
@@ -14,7 +14,11 @@ type ModuleClusterConfig struct {
 	Mode    EtlMode `yaml:"mode" json:"mode"`
 	OnCrash OnCrash `yaml:"on-crash" json:"on-crash"`
 	OnLoad  OnLoad  `yaml:"on-load" json:"on-load"`
-	Static  struct {
+	Restart struct {
+		Max   int `yaml:"max,omitempty" json:"max,omitempty"`     // restart attempts when on-crash is Restart
+		Delay int `yaml:"delay,omitempty" json:"delay,omitempty"` // seconds before the first attempt
+	} `yaml:"restart,omitempty"`
+	Static struct {
 		TFunctions int `yaml:"t-functions" json:"t-functions"`
 		LFunctions int `yaml:"l-functions" json:"l-functions"`
 	} `yaml:"static"`
@@ -22,6 +26,8 @@ type ModuleClusterConfig struct {
 		TFunction DynamicFeatures `yaml:"t-function" json:"t-function"`
 		LFunction DynamicFeatures `yaml:"l-function" json:"l-function"`
 	} `yaml:"dynamic"`
+
+	MaxConcurrency int `yaml:"max-concurrency,omitempty" json:"max-concurrency,omitempty"` // supervisors of the cluster that can run at once
 }
 
 type ModuleCluster struct {
@@ -40,6 +46,8 @@ type ModuleConfig struct {
 	Version float64         `yaml:"version" json:"version"`
 	Contact ModuleContact   `yaml:"contact,omitempty" json:"contact,omitempty"`
 	Exports []ModuleCluster `yaml:"exports" json:"clusters"`
+
+	MaxConcurrency int `yaml:"max-concurrency,omitempty" json:"max-concurrency,omitempty"` // supervisors of the module that can run at once
 }
 
 func (c ModuleCluster) ToClusterConfig() Config {
@@ -54,6 +62,9 @@ func (c ModuleCluster) ToClusterConfig() Config {
 		ETChannelGrowthFactor:       c.Config.Dynamic.TFunction.GrowthFactor,
 		TLChannelThreshold:          c.Config.Dynamic.LFunction.Threshold,
 		TLChannelGrowthFactor:       c.Config.Dynamic.LFunction.GrowthFactor,
+		MaxRestarts:                 c.Config.Restart.Max,
+		RestartDelay:                c.Config.Restart.Delay,
+		MaxConcurrency:              c.Config.MaxConcurrency,
 	}
 }
 
@@ -3,4 +3,6 @@ package interfaces
 type ProcessorConfig struct {
 	Host string `json:"host"`
 	Port int    `json:"port"`
+
+	MaxConcurrency int `json:"max-concurrency,omitempty"` // supervisors the processor can run at once, 0 for no limit
 }
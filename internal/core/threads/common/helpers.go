@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/GabeCordo/cluster-tools/internal/core/components/database"
 	"github.com/GabeCordo/cluster-tools/internal/core/components/processor"
+	"github.com/GabeCordo/cluster-tools/internal/core/components/scheduler"
 	"github.com/GabeCordo/cluster-tools/internal/core/components/supervisor"
 	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
 	"github.com/GabeCordo/toolchain/multithreaded"
@@ -280,13 +281,13 @@ func GetClusters(mandatory ThreadMandatory, moduleName string) (clusters []proce
 }
 
 func CreateSupervisor(mandatory ThreadMandatory,
-	moduleName, clusterName, configName string, metadata map[string]string) (uint64, error) {
+	moduleName, clusterName, configName string, data SupervisorRequestData) (uint64, error) {
 
 	request := ThreadRequest{
 		Action:      CreateAction,
 		Type:        SupervisorRecord,
-		Identifiers: RequestIdentifiers{Module: moduleName, Cluster: clusterName, Config: configName},
-		Data:        metadata,
+		Identifiers: RequestIdentifiers{Module: moduleName, Cluster: clusterName, Config: configName, Job: data.Job},
+		Data:        data,
 		Nonce:       rand.Uint32(),
 	}
 	mandatory.Pipe <- request
@@ -302,7 +303,9 @@ func CreateSupervisor(mandatory ThreadMandatory,
 	return (response.Data).(uint64), response.Error
 }
 
-func GetSupervisor(mandatory ThreadMandatory, filter supervisor.Filter) ([]*supervisor.Supervisor, error) {
+// GetSupervisor
+// Returns the page of supervisors selected by the filter, newest first.
+func GetSupervisor(mandatory ThreadMandatory, filter supervisor.Filter) (supervisor.Page, error) {
 
 	request := ThreadRequest{
 		Action: GetAction,
@@ -312,22 +315,23 @@ func GetSupervisor(mandatory ThreadMandatory, filter supervisor.Filter) ([]*supe
 			Cluster:    filter.Cluster,
 			Supervisor: filter.Id,
 		},
+		Data:  filter,
 		Nonce: rand.Uint32(),
 	}
 	mandatory.Pipe <- request
 
 	data, didTimeout := multithreaded.SendAndWait(mandatory.ResponseTable, request.Nonce, mandatory.Timeout)
 	if didTimeout {
-		return nil, multithreaded.NoResponseReceived
+		return supervisor.Page{}, multithreaded.NoResponseReceived
 	}
 
 	response := (data).(ThreadResponse)
 
 	if !response.Success {
-		return nil, response.Error
+		return supervisor.Page{}, response.Error
 	}
 
-	return (response.Data).([]*supervisor.Supervisor), nil
+	return (response.Data).(supervisor.Page), nil
 }
 
 func UpdateSupervisor(mandatory ThreadMandatory, data *supervisor.Supervisor) error {
@@ -349,7 +353,128 @@ func UpdateSupervisor(mandatory ThreadMandatory, data *supervisor.Supervisor) er
 	return response.Error
 }
 
-func FindStatistics(mandatory ThreadMandatory, moduleName, clusterName string) (entries []database.Statistic, found bool) {
+// LoadSupervisors
+// Returns the supervisors stored in the database and the identifier the next supervisor is given.
+func LoadSupervisors(mandatory ThreadMandatory) (RegistryData, error) {
+
+	request := ThreadRequest{
+		Action: GetAction,
+		Type:   SupervisorRecord,
+		Nonce:  rand.Uint32(),
+	}
+	mandatory.Pipe <- request
+
+	rsp, didTimeout := multithreaded.SendAndWait(mandatory.ResponseTable, request.Nonce, mandatory.Timeout)
+	if didTimeout {
+		return RegistryData{}, multithreaded.NoResponseReceived
+	}
+
+	response := (rsp).(ThreadResponse)
+	if !response.Success {
+		return RegistryData{}, response.Error
+	}
+
+	return (response.Data).(RegistryData), nil
+}
+
+// StreamSupervisor
+// Subscribes to the transitions, statistics and logs of the supervisors matching the filter,
+// the subscription must be closed once the caller stops reading from it.
+func StreamSupervisor(mandatory ThreadMandatory, filter supervisor.Filter) (*supervisor.Subscription, error) {
+
+	request := ThreadRequest{
+		Action: GetAction,
+		Type:   StreamRecord,
+		Identifiers: RequestIdentifiers{
+			Module:     filter.Module,
+			Cluster:    filter.Cluster,
+			Supervisor: filter.Id,
+		},
+		Nonce: rand.Uint32(),
+	}
+	mandatory.Pipe <- request
+
+	rsp, didTimeout := multithreaded.SendAndWait(mandatory.ResponseTable, request.Nonce, mandatory.Timeout)
+	if didTimeout {
+		return nil, multithreaded.NoResponseReceived
+	}
+
+	response := (rsp).(ThreadResponse)
+	if !response.Success {
+		return nil, response.Error
+	}
+
+	return (response.Data).(*supervisor.Subscription), nil
+}
+
+// GetRetention
+// Returns the retention of finished supervisors in the registry and how many it evicted.
+func GetRetention(mandatory ThreadMandatory) (RetentionData, error) {
+
+	request := ThreadRequest{
+		Action: GetAction,
+		Type:   RetentionRecord,
+		Nonce:  rand.Uint32(),
+	}
+	mandatory.Pipe <- request
+
+	rsp, didTimeout := multithreaded.SendAndWait(mandatory.ResponseTable, request.Nonce, mandatory.Timeout)
+	if didTimeout {
+		return RetentionData{}, multithreaded.NoResponseReceived
+	}
+
+	response := (rsp).(ThreadResponse)
+	if !response.Success {
+		return RetentionData{}, response.Error
+	}
+
+	return (response.Data).(RetentionData), nil
+}
+
+// GetAdmission
+// Returns the supervisors waiting in the admission queue and how long admitted ones waited.
+func GetAdmission(mandatory ThreadMandatory) (AdmissionData, error) {
+
+	request := ThreadRequest{
+		Action: GetAction,
+		Type:   AdmissionRecord,
+		Nonce:  rand.Uint32(),
+	}
+	mandatory.Pipe <- request
+
+	rsp, didTimeout := multithreaded.SendAndWait(mandatory.ResponseTable, request.Nonce, mandatory.Timeout)
+	if didTimeout {
+		return AdmissionData{}, multithreaded.NoResponseReceived
+	}
+
+	response := (rsp).(ThreadResponse)
+	if !response.Success {
+		return AdmissionData{}, response.Error
+	}
+
+	return (response.Data).(AdmissionData), nil
+}
+
+func CancelSupervisor(mandatory ThreadMandatory, id uint64) error {
+
+	request := ThreadRequest{
+		Action:      CancelAction,
+		Type:        SupervisorRecord,
+		Identifiers: RequestIdentifiers{Supervisor: id},
+		Nonce:       rand.Uint32(),
+	}
+	mandatory.Pipe <- request
+
+	rsp, didTimeout := multithreaded.SendAndWait(mandatory.ResponseTable, request.Nonce, mandatory.Timeout)
+	if didTimeout {
+		return multithreaded.NoResponseReceived
+	}
+
+	response := (rsp).(ThreadResponse)
+	return response.Error
+}
+
+func FindStatistics(mandatory ThreadMandatory, moduleName, clusterName string, selector interfaces.Selector) (entries []database.Statistic, found bool) {
 
 	databaseRequest := ThreadRequest{
 		Action: GetAction,
@@ -358,6 +483,7 @@ func FindStatistics(mandatory ThreadMandatory, moduleName, clusterName string) (
 			Module:  moduleName,
 			Cluster: clusterName,
 		},
+		Data:  selector,
 		Nonce: rand.Uint32(),
 	}
 	mandatory.Pipe <- databaseRequest
@@ -530,9 +656,7 @@ func StoreInCache(mandatory ThreadMandatory, data any, expiry float64) (identifi
 	rsp, didTimeout := multithreaded.SendAndWait(mandatory.ResponseTable, request.Nonce, mandatory.Timeout)
 
 	if didTimeout {
-		success = false
-	} else {
-
+		return "", false
 	}
 
 	response := (rsp).(ThreadResponse)
@@ -648,7 +772,27 @@ func DeleteJob(mandatory ThreadMandatory, filter *interfaces.Filter) error {
 	return response.Error
 }
 
-func JobQueue(mandatory ThreadMandatory) ([]interfaces.Job, error) {
+func SetJobPaused(mandatory ThreadMandatory, identifier string, paused bool) error {
+
+	request := ThreadRequest{
+		Action:      UpdateAction,
+		Type:        JobRecord,
+		Identifiers: RequestIdentifiers{Job: identifier},
+		Data:        JobStateData{Paused: paused},
+		Nonce:       rand.Uint32(),
+	}
+	mandatory.Pipe <- request
+
+	rsp, didTimeout := multithreaded.SendAndWait(mandatory.ResponseTable, request.Nonce, mandatory.Timeout)
+	if didTimeout {
+		return multithreaded.NoResponseReceived
+	}
+
+	response := (rsp).(ThreadResponse)
+	return response.Error
+}
+
+func JobQueue(mandatory ThreadMandatory) ([]scheduler.Trigger, error) {
 
 	request := ThreadRequest{
 		Action: GetAction,
@@ -663,7 +807,90 @@ func JobQueue(mandatory ThreadMandatory) ([]interfaces.Job, error) {
 	}
 
 	response := (rsp).(ThreadResponse)
-	return (response.Data).([]interfaces.Job), response.Error
+	return (response.Data).([]scheduler.Trigger), response.Error
+}
+
+func GetJobHistory(mandatory ThreadMandatory, identifier string) ([]database.Run, error) {
+
+	request := ThreadRequest{
+		Action:      GetAction,
+		Type:        HistoryRecord,
+		Identifiers: RequestIdentifiers{Job: identifier},
+		Nonce:       rand.Uint32(),
+	}
+	mandatory.Pipe <- request
+
+	rsp, didTimeout := multithreaded.SendAndWait(mandatory.ResponseTable, request.Nonce, mandatory.Timeout)
+	if didTimeout {
+		return nil, multithreaded.NoResponseReceived
+	}
+
+	response := (rsp).(ThreadResponse)
+	if !response.Success {
+		return nil, response.Error
+	}
+
+	return (response.Data).([]database.Run), nil
+}
+
+func GetBlackouts(mandatory ThreadMandatory) ([]interfaces.Blackout, error) {
+
+	request := ThreadRequest{
+		Action: GetAction,
+		Type:   BlackoutRecord,
+		Nonce:  rand.Uint32(),
+	}
+	mandatory.Pipe <- request
+
+	rsp, didTimeout := multithreaded.SendAndWait(mandatory.ResponseTable, request.Nonce, mandatory.Timeout)
+	if didTimeout {
+		return nil, multithreaded.NoResponseReceived
+	}
+
+	response := (rsp).(ThreadResponse)
+	if !response.Success {
+		return nil, response.Error
+	}
+
+	return (response.Data).([]interfaces.Blackout), nil
+}
+
+func CreateBlackout(mandatory ThreadMandatory, blackout *interfaces.Blackout) error {
+
+	request := ThreadRequest{
+		Action: CreateAction,
+		Type:   BlackoutRecord,
+		Data:   *blackout,
+		Nonce:  rand.Uint32(),
+	}
+	mandatory.Pipe <- request
+
+	rsp, didTimeout := multithreaded.SendAndWait(mandatory.ResponseTable, request.Nonce, mandatory.Timeout)
+	if didTimeout {
+		return multithreaded.NoResponseReceived
+	}
+
+	response := (rsp).(ThreadResponse)
+	return response.Error
+}
+
+func DeleteBlackout(mandatory ThreadMandatory, name string) error {
+
+	request := ThreadRequest{
+		Action: DeleteAction,
+		Type:   BlackoutRecord,
+		Data:   name,
+		Nonce:  rand.Uint32(),
+	}
+	mandatory.Pipe <- request
+
+	rsp, didTimeout := multithreaded.SendAndWait(mandatory.ResponseTable, request.Nonce, mandatory.Timeout)
+	if didTimeout {
+		return multithreaded.NoResponseReceived
+	}
+
+	response := (rsp).(ThreadResponse)
+	return response.Error
 }
 
 func GetSubscribers(mandatory ThreadMandatory) ([]string, error) {
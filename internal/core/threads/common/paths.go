@@ -11,4 +11,6 @@ var (
 	DefaultStatisticsFolder = DefaultFrameworkFolder + "statistics/"
 	DefaultSchedulesFolder  = DefaultFrameworkFolder + "schedules/"
 	DefaultMessengerFolder  = DefaultFrameworkFolder + "messenger/"
+	DefaultHistoryFolder    = DefaultFrameworkFolder + "history/"
+	DefaultSupervisorFolder = DefaultFrameworkFolder + "supervisors/"
 )
@@ -1,5 +1,11 @@
 package common
 
+import (
+	"github.com/GabeCordo/cluster-tools/internal/core/components/supervisor"
+	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+	"time"
+)
+
 type RequestCaller uint8
 
 const (
@@ -21,6 +27,7 @@ const (
 	WipeAction
 	CloseAction
 	ToggleAction
+	CancelAction
 )
 
 type RequestType uint16
@@ -43,6 +50,11 @@ const (
 	ContactRecord
 	EmailRecord
 	SubscriptionRecord
+	HistoryRecord
+	BlackoutRecord
+	RetentionRecord
+	StreamRecord
+	AdmissionRecord
 )
 
 type RequestIdentifiers struct {
@@ -51,6 +63,7 @@ type RequestIdentifiers struct {
 	Cluster    string
 	Config     string
 	Supervisor uint64
+	Job        string
 }
 
 type ThreadRequest struct {
@@ -76,6 +89,70 @@ type ProcessorResponseData struct {
 	Data       any
 }
 
+type SupervisorRequestData struct {
+	Metadata map[string]string
+	Labels   map[string]string
+	Job      string // the scheduled job the supervisor is provisioned for
+	Original uint64 // the first supervisor of a crashed run being restarted
+	Attempt  int    // the restart attempt the supervisor is provisioned for
+	Avoid    string // the processor the run crashed on, avoided if another is available
+	Priority int    // queued supervisors with a higher priority are admitted first
+
+	IdempotencyKey string // a retried request with the key returns the supervisor it created
+
+	Processors []string          // the processors that can run the supervisor, in the order they are tried
+	Limits     supervisor.Limits // the module and processor limits, the cluster limit is part of its config
+}
+
+type StatisticData struct {
+	Statistics *interfaces.Statistics
+	Labels     map[string]string // of the supervisor the statistics were recorded for
+}
+
+type RegistryData struct {
+	Supervisors []*supervisor.Supervisor
+	Next        uint64 // the identifier the next supervisor is given
+}
+
+// EvictionData
+// How a supervisor evicted from the registry is removed from the database.
+type EvictionData struct {
+	Archive bool // kept on disk for auditing but never restored, instead of being deleted
+}
+
+// RetentionData
+// The retention of finished supervisors in the registry and how many it has evicted.
+type RetentionData struct {
+	Retention supervisor.Retention `json:"retention"`
+	Interval  float64              `json:"interval"` // seconds between collections
+	Archive   bool                 `json:"archive"`  // evicted supervisors are archived instead of deleted
+	Retained  int                  `json:"retained"` // supervisors in the registry
+	Evicted   uint64               `json:"evicted"`  // supervisors evicted since the core started
+	Deleted   uint64               `json:"deleted"`  // evicted supervisors also deleted from the database
+	Collected time.Time            `json:"collected,omitempty"`
+}
+
+// AdmissionData
+// The supervisors waiting for a slot under the concurrency limits and how long the
+// supervisors admitted from the queue waited.
+type AdmissionData struct {
+	Depth       int                  `json:"depth"`
+	LongestWait float64              `json:"longest-wait"` // seconds the oldest queued supervisor has waited
+	Admitted    uint64               `json:"admitted"`     // supervisors admitted from the queue since the core started
+	AverageWait float64              `json:"average-wait"` // seconds an admitted supervisor waited on average
+	Waiting     []supervisor.Waiting `json:"waiting"`
+}
+
+type JobEventData struct {
+	Supervisor uint64
+	Status     string // the terminal status the supervisor reached
+	Completed  bool   // false if the supervisor crashed or was terminated
+}
+
+type JobStateData struct {
+	Paused bool
+}
+
 type CacheRequestData struct {
 	Data       any
 	Identifier string
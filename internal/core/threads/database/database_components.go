@@ -48,3 +48,17 @@ func GetJobDatabaseInstance(t ...string) database.JobDatabase {
 	}
 	return jobDatabase
 }
+
+var supervisorDatabase database.SupervisorDatabase
+
+func GetSupervisorDatabaseInstance(t ...string) database.SupervisorDatabase {
+	if supervisorDatabase == nil {
+
+		if len(t) == 0 || (len(t) == 1 && t[0] == "file") {
+			supervisorDatabase = database.NewLocalSupervisorDatabase()
+		} else {
+			supervisorDatabase, _ = database.NewMongoSupervisorDatabase(mongoUrl)
+		}
+	}
+	return supervisorDatabase
+}
@@ -2,9 +2,11 @@ package database
 
 import (
 	"github.com/GabeCordo/cluster-tools/internal/core/components/database"
+	"github.com/GabeCordo/cluster-tools/internal/core/components/supervisor"
 	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
 	"github.com/GabeCordo/cluster-tools/internal/core/threads/common"
 	"log"
+	"os"
 	"time"
 )
 
@@ -22,6 +24,18 @@ func (thread *Thread) Setup() {
 		}
 	}
 
+	// the folder is new, so cores initialised before supervisors were stored won't have it
+	if err := os.MkdirAll(common.DefaultSupervisorFolder, 0700); err != nil {
+		thread.logger.Printf("could not create the supervisors folder %s\n", err.Error())
+	}
+
+	if db, ok := (GetSupervisorDatabaseInstance()).(database.Database); ok {
+
+		if err := db.Load(common.DefaultSupervisorFolder); err != nil {
+			thread.logger.Printf("failed to load the supervisor registry %s\n", err.Error())
+		}
+	}
+
 	// some configs may have carried over from previous runs
 	// let the operator know these configs are being loaded into the
 	// core without having to query the database over HTTP
@@ -47,9 +61,24 @@ func (thread *Thread) Teardown() {
 		}
 	}
 
+	thread.saveSupervisors()
+
 	thread.wg.Wait()
 }
 
+// saveSupervisors
+// Writes the supervisor registry to disk when it is stored locally, the registry is saved
+// on every change so it survives the core stopping without a teardown.
+func (thread *Thread) saveSupervisors() {
+
+	if db, ok := (GetSupervisorDatabaseInstance()).(database.Database); ok {
+
+		if err := db.Save(common.DefaultSupervisorFolder); err != nil {
+			thread.logger.Printf("failed to save the supervisor registry %s\n", err.Error())
+		}
+	}
+}
+
 func (thread *Thread) Start() {
 
 	// LISTEN FOR INCOMING REQUESTS
@@ -183,12 +212,19 @@ func (thread *Thread) ProcessIncomingRequest(request *common.ThreadRequest) {
 				}
 			case common.StatisticRecord:
 				{
-					if statisticsData, ok := (request.Data).(*interfaces.Statistics); ok {
+					// statistics without labels can still be stored on their own
+					statisticsData, ok := (request.Data).(common.StatisticData)
+					if statistics, isStatistics := (request.Data).(*interfaces.Statistics); isStatistics {
+						statisticsData, ok = common.StatisticData{Statistics: statistics}, true
+					}
+
+					if ok && (statisticsData.Statistics != nil) {
 						err := GetStatisticDatabaseInstance().Create(
 							request.Identifiers.Module, request.Identifiers.Cluster,
 							database.Statistic{ // TODO : depreciate or fix elapsed time
 								Timestamp: time.Now(),
-								Stats:     *statisticsData, // copy
+								Stats:     *statisticsData.Statistics, // copy
+								Labels:    statisticsData.Labels,
 							})
 
 						if db, ok := (GetStatisticDatabaseInstance()).(database.Database); (err == nil) && ok {
@@ -235,13 +271,27 @@ func (thread *Thread) ProcessIncomingRequest(request *common.ThreadRequest) {
 				}
 			case common.StatisticRecord:
 				{
+					selector, _ := (request.Data).(interfaces.Selector)
 					records, err := GetStatisticDatabaseInstance().Get(database.StatisticFilter{
-						Module:  request.Identifiers.Module,
-						Cluster: request.Identifiers.Cluster,
+						Module:   request.Identifiers.Module,
+						Cluster:  request.Identifiers.Cluster,
+						Selector: selector,
 					})
 					response = common.ThreadResponse{Success: err == nil, Nonce: request.Nonce, Data: records}
 					thread.Respond(request, &response)
 				}
+			case common.SupervisorRecord:
+				{
+					data := common.RegistryData{}
+					var err error
+
+					if data.Supervisors, err = GetSupervisorDatabaseInstance().GetAll(); err == nil {
+						data.Next, err = GetSupervisorDatabaseInstance().Next()
+					}
+
+					response = common.ThreadResponse{Success: err == nil, Nonce: request.Nonce, Data: data, Error: err}
+					thread.Respond(request, &response)
+				}
 			case common.JobRecord:
 				{
 
@@ -273,6 +323,21 @@ func (thread *Thread) ProcessIncomingRequest(request *common.ThreadRequest) {
 					response := common.ThreadResponse{Success: err == nil, Nonce: request.Nonce}
 					thread.Respond(request, &response)
 				}
+			case common.SupervisorRecord:
+				{
+					var err error
+					if eviction, ok := (request.Data).(common.EvictionData); ok && eviction.Archive {
+						err = GetSupervisorDatabaseInstance().Archive(request.Identifiers.Supervisor)
+					} else {
+						err = GetSupervisorDatabaseInstance().Delete(request.Identifiers.Supervisor)
+					}
+					if err == nil {
+						thread.saveSupervisors()
+					}
+
+					response := common.ThreadResponse{Success: err == nil, Nonce: request.Nonce, Error: err}
+					thread.Respond(request, &response)
+				}
 			case common.JobRecord:
 				{
 
@@ -292,6 +357,18 @@ func (thread *Thread) ProcessIncomingRequest(request *common.ThreadRequest) {
 
 				response := common.ThreadResponse{Success: err == nil, Nonce: request.Nonce}
 				thread.Respond(request, &response)
+			case common.SupervisorRecord:
+				var err error
+				if instance, ok := (request.Data).(*supervisor.Supervisor); ok {
+					if err = GetSupervisorDatabaseInstance().Store(instance); err == nil {
+						thread.saveSupervisors()
+					}
+				} else {
+					err = StoreTypeMismatch
+				}
+
+				response := common.ThreadResponse{Success: err == nil, Nonce: request.Nonce, Error: err}
+				thread.Respond(request, &response)
 			}
 		}
 	}
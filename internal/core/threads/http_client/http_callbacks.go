@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -209,8 +210,16 @@ type SupervisorConfigJSONBody struct {
 	Config     string            `json:"config"`
 	Supervisor uint64            `json:"id,omitempty"`
 	Metadata   map[string]string `json:"metadata,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Priority   int               `json:"priority,omitempty"` // queued supervisors with a higher priority are admitted first
+
+	IdempotencyKey string `json:"idempotency-key,omitempty"` // also accepted as the Idempotency-Key header
 }
 
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+)
+
 type SupervisorProvisionJSONResponse struct {
 	Cluster    string `json:"cluster,omitempty"`
 	Supervisor uint64 `json:"id,omitempty"`
@@ -231,51 +240,161 @@ func (thread *Thread) getSupervisorCallback(w http.ResponseWriter, r *http.Reque
 
 	urlMapping, _ := url.ParseQuery(r.URL.RawQuery)
 
-	module := ""
-	if moduleStr, found := urlMapping["module"]; found {
-		module = moduleStr[0]
-	}
-
-	cluster := ""
-	if clusterStr, found := urlMapping["cluster"]; found {
-		cluster = clusterStr[0]
+	filter, err := supervisorFilter(urlMapping, time.Now())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
 	}
 
-	var id uint64
-	if idStr, found := urlMapping["id"]; found {
-		if tmp, err := strconv.ParseUint(idStr[0], 10, 64); err == nil {
-			id = tmp
-		} else {
-			id = 0
+	// a list of every supervisor ever stored is never returned, it has to be paged through
+	if !paginated(urlMapping) {
+		if filter.Empty() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
 		}
-	} else {
-		id = 0
-	}
-
-	if (module == "") && (cluster == "") && (id == 0) {
-		w.WriteHeader(http.StatusBadRequest)
-		return
+		filter.Limit = supervisor.Unlimited
 	}
 
 	response := &interfaces.HTTPResponse{Success: true}
 
 	mandatory := common.ThreadMandatory{thread.C5, thread.ProcessorResponseTable, thread.config.Timeout}
-	filter := supervisor.Filter{Module: module, Cluster: cluster, Id: id}
 
-	instance, err := common.GetSupervisor(mandatory, filter)
-	if err != nil {
+	page, err := common.GetSupervisor(mandatory, filter)
+	if errors.Is(err, supervisor.InvalidCursor) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	} else if err != nil {
 		response.Success = false
 		response.Description = err.Error()
+	} else if paginated(urlMapping) {
+		response.Data = page
 	} else {
-		response.Data = instance
+		// clients that do not page through the supervisors still receive a bare list
+		response.Data = page.Supervisors
 	}
 
 	b, _ := json.Marshal(response)
 	w.Write(b)
 }
 
+// paginated
+// Returns true if the query asks for a page of supervisors, {"supervisors": [...], "next": ...},
+// instead of the list of supervisors returned before queries could be paged through.
+func paginated(urlMapping url.Values) bool {
+	return urlMapping.Has("limit") || urlMapping.Has("cursor")
+}
+
+// supervisorFilter
+// Builds the filter of a supervisor query from the url parameters. Times are RFC3339 or
+// a duration before now, such as 24h, statuses are separated by commas and the selector
+// is matched against the labels, ex. team=billing,env!=dev.
+func supervisorFilter(urlMapping url.Values, now time.Time) (supervisor.Filter, error) {
+
+	filter := supervisor.Filter{
+		Module:    urlMapping.Get("module"),
+		Cluster:   urlMapping.Get("cluster"),
+		Processor: urlMapping.Get("processor"),
+		Metadata:  urlMapping.Get("metadata"),
+		Cursor:    urlMapping.Get("cursor"),
+	}
+
+	if idStr := urlMapping.Get("id"); idStr != "" {
+		id, err := strconv.ParseUint(idStr, 10, 64)
+		if err != nil {
+			return filter, err
+		}
+		filter.Id = id
+	}
+
+	if statusStr := urlMapping.Get("status"); statusStr != "" {
+		for _, name := range strings.Split(statusStr, ",") {
+			status, found := supervisor.ParseStatus(strings.TrimSpace(name))
+			if !found {
+				return filter, fmt.Errorf("unknown supervisor status %s", name)
+			}
+			filter.Status = append(filter.Status, status)
+		}
+	}
+
+	selector, err := interfaces.ParseSelector(urlMapping.Get("selector"))
+	if err != nil {
+		return filter, err
+	}
+	filter.Selector = selector
+
+	if limitStr := urlMapping.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if (err != nil) || (limit <= 0) {
+			return filter, fmt.Errorf("expected the limit to be a positive number")
+		}
+		filter.Limit = limit
+	}
+
+	times := map[string]*time.Time{
+		"created-after":   &filter.CreatedAfter,
+		"created-before":  &filter.CreatedBefore,
+		"finished-after":  &filter.FinishedAfter,
+		"finished-before": &filter.FinishedBefore,
+	}
+
+	for key, t := range times {
+
+		value := urlMapping.Get(key)
+		if value == "" {
+			continue
+		}
+
+		if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+			*t = parsed
+		} else if duration, err := time.ParseDuration(value); err == nil {
+			*t = now.Add(-duration)
+		} else {
+			return filter, fmt.Errorf("expected %s to be a RFC3339 time or a duration", key)
+		}
+	}
+
+	return filter, nil
+}
+
+func (thread *Thread) supervisorQueueCallback(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method == http.MethodGet {
+		thread.getSupervisorQueueCallback(w, r)
+	} else {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// getSupervisorQueueCallback
+// Returns the supervisors waiting for a slot under the concurrency limits, in the order
+// they will be admitted, and how long admitted supervisors waited.
+func (thread *Thread) getSupervisorQueueCallback(w http.ResponseWriter, r *http.Request) {
+
+	response := interfaces.HTTPResponse{}
+
+	var err error
+	response.Data, err = common.GetAdmission(common.ThreadMandatory{thread.C5, thread.ProcessorResponseTable, thread.config.Timeout})
+
+	if err != nil {
+		response.Description = err.Error()
+	}
+	response.Success = err == nil
+
+	if b, err := json.Marshal(response); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	} else {
+		w.Write(b)
+	}
+}
+
 func (thread *Thread) postSupervisorCallback(w http.ResponseWriter, r *http.Request) {
 
+	// an action on an existing supervisor is passed in the query
+	if r.URL.Query().Has("action") {
+		thread.postSupervisorActionCallback(w, r)
+		return
+	}
+
 	var request SupervisorConfigJSONBody
 
 	err := json.NewDecoder(r.Body).Decode(&request)
@@ -284,7 +403,45 @@ func (thread *Thread) postSupervisorCallback(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	if supervisorId, err := common.CreateSupervisor(
+	supervisorId, status := thread.createSupervisor(r, &request)
+	if status != http.StatusOK {
+		w.WriteHeader(status)
+		return
+	}
+
+	response := &SupervisorProvisionJSONResponse{Cluster: request.Cluster, Supervisor: supervisorId}
+	bytes, _ := json.Marshal(response)
+	if _, err := w.Write(bytes); err != nil {
+		// TODO : support module is not mounted
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// createSupervisor
+// Provisions the supervisor described by the request body, returns the http status to
+// respond with if it could not be provisioned.
+func (thread *Thread) createSupervisor(r *http.Request, request *SupervisorConfigJSONBody) (uint64, int) {
+
+	// a retried request carries the same key so the supervisor is not provisioned twice
+	if key := r.Header.Get(IdempotencyKeyHeader); key != "" {
+		if (request.IdempotencyKey != "") && (request.IdempotencyKey != key) {
+			return 0, http.StatusBadRequest
+		}
+		request.IdempotencyKey = key
+	}
+
+	if err := interfaces.ValidateLabels(request.Labels); err != nil {
+		return 0, http.StatusBadRequest
+	}
+
+	data := common.SupervisorRequestData{
+		Metadata:       request.Metadata,
+		Labels:         request.Labels,
+		Priority:       request.Priority,
+		IdempotencyKey: request.IdempotencyKey,
+	}
+
+	supervisorId, err := common.CreateSupervisor(
 		common.ThreadMandatory{
 			thread.C5,
 			thread.ProcessorResponseTable,
@@ -293,18 +450,54 @@ func (thread *Thread) postSupervisorCallback(w http.ResponseWriter, r *http.Requ
 		request.Module,
 		request.Cluster,
 		request.Config,
-		request.Metadata,
-	); err == nil {
+		data,
+	)
 
-		response := &SupervisorProvisionJSONResponse{Cluster: request.Cluster, Supervisor: supervisorId}
-		bytes, _ := json.Marshal(response)
-		if _, err := w.Write(bytes); err != nil {
-			// TODO : support module is not mounted
-			w.WriteHeader(http.StatusInternalServerError)
+	if errors.Is(err, supervisor.IdempotencyKeyReused) {
+		return 0, http.StatusConflict
+	} else if err != nil {
+		return 0, http.StatusBadRequest
+	}
+
+	return supervisorId, http.StatusOK
+}
+
+func (thread *Thread) postSupervisorActionCallback(w http.ResponseWriter, r *http.Request) {
+
+	urlMapping, _ := url.ParseQuery(r.URL.RawQuery)
+
+	var id uint64
+	if idStr, found := urlMapping["id"]; found {
+		if tmp, err := strconv.ParseUint(idStr[0], 10, 64); err == nil {
+			id = tmp
 		}
-	} else {
+	}
+
+	action := urlMapping.Get("action")
+
+	if (id == 0) || (action != "cancel") {
 		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := &interfaces.HTTPResponse{}
+
+	mandatory := common.ThreadMandatory{thread.C5, thread.ProcessorResponseTable, thread.config.Timeout}
+	err := common.CancelSupervisor(mandatory, id)
+
+	if errors.Is(err, supervisor.DoesNotExist) {
+		w.WriteHeader(http.StatusNotFound)
+	} else if errors.Is(err, supervisor.NotCancellable) {
+		w.WriteHeader(http.StatusConflict)
 	}
+
+	response.Success = err == nil
+	if err != nil {
+		response.Description = err.Error()
+	}
+
+	b, _ := json.Marshal(response)
+	w.Write(b)
 }
 
 func (thread *Thread) configCallback(w http.ResponseWriter, r *http.Request) {
@@ -391,8 +584,10 @@ func (thread *Thread) statisticCallback(w http.ResponseWriter, r *http.Request)
 		moduleName, moduleNameFound := urlMapping["module"]
 		clusterName, clusterNameFound := urlMapping["cluster"]
 
-		if moduleNameFound && clusterNameFound {
-			statistics, found := common.FindStatistics(mandatory, moduleName[0], clusterName[0])
+		selector, err := interfaces.ParseSelector(urlMapping.Get("selector"))
+
+		if moduleNameFound && clusterNameFound && (err == nil) {
+			statistics, found := common.FindStatistics(mandatory, moduleName[0], clusterName[0], selector)
 			if found {
 				bytes, err := json.Marshal(statistics)
 				if err == nil {
@@ -428,10 +623,21 @@ func (thread *Thread) getDebugCallback(w http.ResponseWriter, r *http.Request) {
 
 	thread.logger.Printf("ping from %s\n", r.RemoteAddr)
 	response := interfaces.HTTPResponse{Success: true, Description: "bonjour"}
+
+	// the ping still succeeds if the supervisor thread is too busy to report its retention
+	mandatory := common.ThreadMandatory{thread.C5, thread.ProcessorResponseTable, thread.config.Timeout}
+	if retention, err := common.GetRetention(mandatory); err == nil {
+		response.Data = DebugData{Supervisors: &retention}
+	}
+
 	b, _ := json.Marshal(response)
 	w.Write(b)
 }
 
+type DebugData struct {
+	Supervisors *common.RetentionData `json:"supervisors,omitempty"`
+}
+
 type DebugJSONBody struct {
 	Action string `json:"action"`
 }
@@ -471,6 +677,8 @@ func (thread *Thread) jobCallback(w http.ResponseWriter, r *http.Request) {
 		thread.getJobCallback(w, r)
 	} else if r.Method == http.MethodPost {
 		thread.postJobCallback(w, r)
+	} else if r.Method == http.MethodPut {
+		thread.putJobCallback(w, r)
 	} else if r.Method == http.MethodDelete {
 		thread.deleteJobCallback(w, r)
 	} else {
@@ -501,15 +709,21 @@ func (thread *Thread) getJobCallback(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	selector, err := interfaces.ParseSelector(urlMapping.Get("selector"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
 	filter := &interfaces.Filter{
 		Identifier: identifier,
 		Module:     module,
 		Cluster:    cluster,
 		Interval: interfaces.Interval{
 			Minute: minutes,
-		}}
-
-	var err error
+		},
+		Selector: selector,
+	}
 
 	response := interfaces.HTTPResponse{}
 	response.Data, err = common.GetJobs(common.ThreadMandatory{thread.C20, thread.SchedulerResponseTable, thread.config.Timeout}, filter)
@@ -538,6 +752,10 @@ func (thread *Thread) postJobCallback(w http.ResponseWriter, r *http.Request) {
 
 	response := interfaces.HTTPResponse{}
 	if err := common.CreateJob(common.ThreadMandatory{thread.C20, thread.SchedulerResponseTable, thread.config.Timeout}, &job); err != nil {
+		if errors.Is(err, interfaces.InvalidCronExpression) || errors.Is(err, interfaces.InvalidMetadataTemplate) ||
+			errors.Is(err, interfaces.InvalidLabel) {
+			w.WriteHeader(http.StatusBadRequest)
+		}
 		response.Success = false
 		response.Data = err.Error()
 	} else {
@@ -551,6 +769,45 @@ func (thread *Thread) postJobCallback(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (thread *Thread) putJobCallback(w http.ResponseWriter, r *http.Request) {
+
+	urlMapping, _ := url.ParseQuery(r.URL.RawQuery)
+
+	identifier := ""
+	if tmp, found := urlMapping["identifier"]; found {
+		identifier = tmp[0]
+	}
+	action := ""
+	if tmp, found := urlMapping["action"]; found {
+		action = tmp[0]
+	}
+
+	if (identifier == "") || ((action != "pause") && (action != "resume")) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := interfaces.HTTPResponse{}
+	err := common.SetJobPaused(
+		common.ThreadMandatory{
+			thread.C20,
+			thread.SchedulerResponseTable,
+			thread.config.Timeout},
+		identifier, action == "pause",
+	)
+
+	response.Success = err == nil
+	if err != nil {
+		response.Description = err.Error()
+	}
+
+	if b, err := json.Marshal(response); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	} else {
+		w.Write(b)
+	}
+}
+
 func (thread *Thread) deleteJobCallback(w http.ResponseWriter, r *http.Request) {
 
 	urlMapping, _ := url.ParseQuery(r.URL.RawQuery)
@@ -632,3 +889,139 @@ func (thread *Thread) getJobQueueCallback(w http.ResponseWriter, r *http.Request
 		w.Write(b)
 	}
 }
+
+func (thread *Thread) jobHistoryCallback(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method == http.MethodGet {
+		thread.getJobHistoryCallback(w, r)
+	} else {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (thread *Thread) getJobHistoryCallback(w http.ResponseWriter, r *http.Request) {
+
+	urlMapping, _ := url.ParseQuery(r.URL.RawQuery)
+
+	identifier := ""
+	if tmp, found := urlMapping["identifier"]; found {
+		identifier = tmp[0]
+	}
+
+	if identifier == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := interfaces.HTTPResponse{}
+
+	var err error
+	response.Data, err = common.GetJobHistory(common.ThreadMandatory{thread.C20, thread.SchedulerResponseTable, thread.config.Timeout}, identifier)
+
+	if err != nil {
+		response.Description = err.Error()
+	}
+	response.Success = err == nil
+
+	if b, err := json.Marshal(response); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	} else {
+		w.Write(b)
+	}
+}
+
+func (thread *Thread) jobBlackoutCallback(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method == http.MethodGet {
+		thread.getJobBlackoutCallback(w, r)
+	} else if r.Method == http.MethodPost {
+		thread.postJobBlackoutCallback(w, r)
+	} else if r.Method == http.MethodDelete {
+		thread.deleteJobBlackoutCallback(w, r)
+	} else {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (thread *Thread) getJobBlackoutCallback(w http.ResponseWriter, r *http.Request) {
+
+	response := interfaces.HTTPResponse{}
+
+	var err error
+	response.Data, err = common.GetBlackouts(common.ThreadMandatory{thread.C20, thread.SchedulerResponseTable, thread.config.Timeout})
+
+	if err != nil {
+		response.Description = err.Error()
+	}
+	response.Success = err == nil
+
+	if b, err := json.Marshal(response); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	} else {
+		w.Write(b)
+	}
+}
+
+func (thread *Thread) postJobBlackoutCallback(w http.ResponseWriter, r *http.Request) {
+
+	var blackout interfaces.Blackout
+	if err := json.NewDecoder(r.Body).Decode(&blackout); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// the window is checked here so a bad request is not mistaken for a failure of the core
+	if err := blackout.Validate(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		b, _ := json.Marshal(interfaces.HTTPResponse{Success: false, Description: err.Error()})
+		w.Write(b)
+		return
+	}
+
+	response := interfaces.HTTPResponse{}
+	err := common.CreateBlackout(common.ThreadMandatory{thread.C20, thread.SchedulerResponseTable, thread.config.Timeout}, &blackout)
+
+	response.Success = err == nil
+	if err != nil {
+		response.Description = err.Error()
+	}
+
+	if b, err := json.Marshal(response); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	} else {
+		w.Write(b)
+	}
+}
+
+func (thread *Thread) deleteJobBlackoutCallback(w http.ResponseWriter, r *http.Request) {
+
+	urlMapping, _ := url.ParseQuery(r.URL.RawQuery)
+
+	name := ""
+	if tmp, found := urlMapping["name"]; found {
+		name = tmp[0]
+	}
+
+	if name == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := interfaces.HTTPResponse{}
+	err := common.DeleteBlackout(common.ThreadMandatory{thread.C20, thread.SchedulerResponseTable, thread.config.Timeout}, name)
+
+	if errors.Is(err, interfaces.BlackoutWindowNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+	}
+
+	response.Success = err == nil
+	if err != nil {
+		response.Description = err.Error()
+	}
+
+	if b, err := json.Marshal(response); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	} else {
+		w.Write(b)
+	}
+}
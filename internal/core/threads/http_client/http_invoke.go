@@ -0,0 +1,151 @@
+package http_client
+
+import (
+	"encoding/json"
+	"github.com/GabeCordo/cluster-tools/internal/core/components/supervisor"
+	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+	"github.com/GabeCordo/cluster-tools/internal/core/threads/common"
+	"net/http"
+	"time"
+)
+
+const (
+	DefaultInvokeTimeout = 30  // seconds
+	MaxInvokeTimeout     = 300 // seconds
+)
+
+type InvokeJSONBody struct {
+	SupervisorConfigJSONBody
+	Timeout float64 `json:"timeout,omitempty"` // seconds to wait for the supervisor to finish
+}
+
+type InvokeJSONResponse struct {
+	Supervisor uint64            `json:"id"`
+	Status     supervisor.Status `json:"status"`
+	Result     any               `json:"result,omitempty"`
+}
+
+func (thread *Thread) invokeCallback(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method == http.MethodPost {
+		thread.postInvokeCallback(w, r)
+	} else {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// postInvokeCallback
+// Provisions a supervisor and waits, up to the timeout of the request, for it to finish
+// so the result its processor attached can be returned like a function call. A supervisor
+// still running at the timeout is returned with 202 so the caller can follow it instead.
+func (thread *Thread) postInvokeCallback(w http.ResponseWriter, r *http.Request) {
+
+	var request InvokeJSONBody
+
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	timeout := request.Timeout
+	if timeout <= 0 {
+		timeout = DefaultInvokeTimeout
+	} else if timeout > MaxInvokeTimeout {
+		timeout = MaxInvokeTimeout
+	}
+	deadline := time.NewTimer(time.Duration(timeout * float64(time.Second)))
+	defer deadline.Stop()
+
+	id, status := thread.createSupervisor(r, &request.SupervisorConfigJSONBody)
+	if status != http.StatusOK {
+		w.WriteHeader(status)
+		return
+	}
+
+	mandatory := common.ThreadMandatory{thread.C5, thread.ProcessorResponseTable, thread.config.Timeout}
+	filter := supervisor.Filter{Id: id}
+
+	// subscribe before checking the supervisor so its final transition is not missed
+	subscription, err := common.StreamSupervisor(mandatory, filter)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	defer subscription.Close()
+
+	instance, err := thread.findSupervisor(mandatory, filter)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	for !instance.IsFinished() {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-deadline.C:
+			thread.writeInvoke(w, http.StatusAccepted, instance)
+			return
+		case update, ok := <-subscription.Updates:
+			if !ok {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			if (update.Transition == nil) || !update.Transition.IsFinal() {
+				continue
+			}
+			if instance, err = thread.findSupervisor(mandatory, filter); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+
+	thread.writeInvoke(w, http.StatusOK, instance)
+}
+
+// findSupervisor
+// Returns the only supervisor selected by the filter, DoesNotExist if there is none.
+func (thread *Thread) findSupervisor(mandatory common.ThreadMandatory, filter supervisor.Filter) (*supervisor.Supervisor, error) {
+
+	page, err := common.GetSupervisor(mandatory, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(page.Supervisors) == 0 {
+		return nil, supervisor.DoesNotExist
+	}
+
+	return page.Supervisors[0], nil
+}
+
+// writeInvoke
+// Responds with the status of the supervisor and, once it completed, its result. A result
+// referenced through the cache is fetched so the caller receives the value itself.
+func (thread *Thread) writeInvoke(w http.ResponseWriter, status int, instance *supervisor.Supervisor) {
+
+	data := InvokeJSONResponse{Supervisor: instance.Id, Status: instance.Status}
+	response := &interfaces.HTTPResponse{Success: instance.Status == supervisor.Completed, Data: &data}
+
+	if instance.Result != nil {
+		data.Result = instance.Result.Value
+		if instance.Result.Cache != "" {
+			mandatory := common.ThreadMandatory{thread.C24, thread.CacheResponseTable, thread.config.Timeout}
+			if value, found := common.FetchFromCache(mandatory, instance.Result.Cache); found {
+				data.Result = value
+			} else {
+				response.Description = "the result of the supervisor expired from the cache"
+			}
+		}
+	}
+
+	if (status == http.StatusOK) && !response.Success && (response.Description == "") {
+		response.Description = "supervisor finished as " + string(instance.Status)
+	}
+
+	b, _ := json.Marshal(response)
+	w.WriteHeader(status)
+	w.Write(b)
+}
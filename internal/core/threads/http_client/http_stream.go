@@ -0,0 +1,131 @@
+package http_client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/GabeCordo/cluster-tools/internal/core/components/supervisor"
+	"github.com/GabeCordo/cluster-tools/internal/core/threads/common"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultStreamKeepAlive = 15 // seconds between comments that keep idle streams open
+)
+
+func (thread *Thread) supervisorStreamCallback(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method == "GET" {
+		thread.getSupervisorStreamCallback(w, r)
+	} else {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// getSupervisorStreamCallback
+// Streams the transitions, statistics and logs of the supervisors matching the query as
+// server-sent events. The current state of each supervisor is sent first as a snapshot,
+// a stream for a single supervisor ends once the supervisor finishes.
+func (thread *Thread) getSupervisorStreamCallback(w http.ResponseWriter, r *http.Request) {
+
+	urlMapping, _ := url.ParseQuery(r.URL.RawQuery)
+
+	filter := supervisor.Filter{}
+	if moduleStr, found := urlMapping["module"]; found {
+		filter.Module = moduleStr[0]
+	}
+	if clusterStr, found := urlMapping["cluster"]; found {
+		filter.Cluster = clusterStr[0]
+	}
+	if idStr, found := urlMapping["id"]; found {
+		if tmp, err := strconv.ParseUint(idStr[0], 10, 64); err == nil {
+			filter.Id = tmp
+		}
+	}
+
+	if (filter.Module == "") && (filter.Cluster == "") && (filter.Id == 0) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	mandatory := common.ThreadMandatory{thread.C5, thread.ProcessorResponseTable, thread.config.Timeout}
+
+	// subscribe before the snapshot so no update is missed between the two
+	subscription, err := common.StreamSupervisor(mandatory, filter)
+	if errors.Is(err, supervisor.DoesNotExist) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	defer subscription.Close()
+
+	query := filter
+	query.Limit = supervisor.MaxPageSize
+
+	snapshot, err := common.GetSupervisor(mandatory, query)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	finished := false
+	for _, instance := range snapshot.Supervisors {
+		writeEvent(w, "snapshot", instance)
+		finished = instance.IsFinished()
+	}
+	flusher.Flush()
+
+	if filter.UseId() && finished {
+		return
+	}
+
+	keepAlive := time.NewTicker(DefaultStreamKeepAlive * time.Second)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		case update, ok := <-subscription.Updates:
+			if !ok {
+				return
+			}
+
+			writeEvent(w, string(update.Kind), update)
+			flusher.Flush()
+
+			// nothing else happens to a supervisor after it finishes
+			if filter.UseId() && (update.Kind == supervisor.TransitionUpdate) && update.Transition.IsFinal() {
+				return
+			}
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event string, data any) {
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, b)
+}
@@ -29,6 +29,18 @@ func (thread *Thread) Setup() {
 		thread.supervisorCallback(w, r)
 	})
 
+	mux.HandleFunc("/supervisor/stream", func(w http.ResponseWriter, r *http.Request) {
+		thread.supervisorStreamCallback(w, r)
+	})
+
+	mux.HandleFunc("/supervisor/queue", func(w http.ResponseWriter, r *http.Request) {
+		thread.supervisorQueueCallback(w, r)
+	})
+
+	mux.HandleFunc("/invoke", func(w http.ResponseWriter, r *http.Request) {
+		thread.invokeCallback(w, r)
+	})
+
 	mux.HandleFunc("/statistics", func(w http.ResponseWriter, r *http.Request) {
 		thread.statisticCallback(w, r)
 	})
@@ -45,6 +57,14 @@ func (thread *Thread) Setup() {
 		thread.jobQueueCallback(w, r)
 	})
 
+	mux.HandleFunc("/job/history", func(w http.ResponseWriter, r *http.Request) {
+		thread.jobHistoryCallback(w, r)
+	})
+
+	mux.HandleFunc("/job/blackout", func(w http.ResponseWriter, r *http.Request) {
+		thread.jobBlackoutCallback(w, r)
+	})
+
 	// TODO - explore this more, fucking cool - removed for now
 	if thread.config.Debug {
 		mux.HandleFunc("/debug", func(w http.ResponseWriter, r *http.Request) { thread.debugCallback(w, r) })
@@ -380,6 +380,34 @@ func (thread *Thread) putSupervisorCallback(w http.ResponseWriter, r *http.Reque
 	}
 
 	response := &interfaces.HTTPResponse{}
+
+	// the result is kept in the cache so the supervisor record only holds a reference to it
+	if (instance.Result != nil) && (instance.Result.Value != nil) {
+
+		expiry := thread.config.ResultExpiry
+		if expiry <= 0 {
+			expiry = DefaultResultExpiry
+		}
+
+		key, stored := common.StoreInCache(
+			common.ThreadMandatory{
+				thread.C9,
+				thread.CacheResponseTable,
+				thread.config.Timeout,
+			},
+			instance.Result.Value,
+			expiry,
+		)
+		if !stored {
+			w.WriteHeader(http.StatusInternalServerError)
+			response.Description = "could not store the result of the supervisor"
+			b, _ := json.Marshal(response)
+			w.Write(b)
+			return
+		}
+		instance.Result = &supervisor.Result{Cache: key}
+	}
+
 	err = common.UpdateSupervisor(
 		common.ThreadMandatory{
 			thread.C7,
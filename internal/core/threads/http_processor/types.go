@@ -15,9 +15,14 @@ type Config struct {
 		Host string
 		Port int
 	}
-	Timeout float64
+	Timeout      float64
+	ResultExpiry float64 // minutes the result of a supervisor is kept in the cache
 }
 
+const (
+	DefaultResultExpiry = 60 // minutes
+)
+
 type Thread struct {
 	mutex sync.Mutex
 
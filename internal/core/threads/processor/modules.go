@@ -39,7 +39,7 @@ func (thread *Thread) addModule(processorName string, cfg *interfaces.ModuleConf
 					Config:    export.Cluster,
 				},
 				Caller: common.System,
-				Data:   make(map[string]string),
+				Data:   common.SupervisorRequestData{Metadata: make(map[string]string)},
 				Nonce:  rand.Uint32(),
 			}
 		}
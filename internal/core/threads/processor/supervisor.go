@@ -9,7 +9,7 @@ import (
 	"math/rand"
 )
 
-func (thread *Thread) getSupervisor(r *common.ThreadRequest) ([]*supervisor.Supervisor, error) {
+func (thread *Thread) getSupervisor(r *common.ThreadRequest) (supervisor.Page, error) {
 
 	// processor -> all supervisor ids on the processor
 	//	-	id
@@ -27,6 +27,7 @@ func (thread *Thread) getSupervisor(r *common.ThreadRequest) ([]*supervisor.Supe
 		Action:      common.GetAction,
 		Type:        common.SupervisorRecord,
 		Identifiers: r.Identifiers,
+		Data:        r.Data, // may contain a supervisor.Filter
 		Nonce:       r.Nonce,
 	}
 	thread.C13 <- request
@@ -35,12 +36,15 @@ func (thread *Thread) getSupervisor(r *common.ThreadRequest) ([]*supervisor.Supe
 		thread.config.Timeout)
 
 	if didTimeout {
-		return nil, multithreaded.NoResponseReceived
+		return supervisor.Page{}, multithreaded.NoResponseReceived
 	}
 
 	response := (rsp).(common.ThreadResponse)
+	if response.Error != nil {
+		return supervisor.Page{}, response.Error
+	}
 
-	return (response.Data).([]*supervisor.Supervisor), nil
+	return (response.Data).(supervisor.Page), nil
 }
 
 func (thread *Thread) createSupervisor(r *common.ThreadRequest) (uint64, error) {
@@ -77,14 +81,33 @@ func (thread *Thread) createSupervisor(r *common.ThreadRequest) (uint64, error)
 		Nonce:       rand.Uint32(),
 	}
 
-	processorInstance := clusterInstance.SelectProcessor()
-	request.Identifiers.Processor = processorInstance.ToString()
+	// a restarted supervisor is moved off the processor it crashed on when possible
+	data, ok := (r.Data).(common.SupervisorRequestData)
+	candidates := clusterInstance.Candidates(data.Avoid)
+	if len(candidates) == 0 {
+		return 0, processor.NoProcessorAvailable
+	}
+	request.Identifiers.Processor = candidates[0].ToString()
+
+	// the supervisor thread admits the supervisor to the first candidate under its limit
+	if ok {
+		data.Processors = make([]string, len(candidates))
+		data.Limits = supervisor.Limits{Module: moduleInstance.MaxConcurrency(), Processors: make(map[string]int)}
+		for idx, candidate := range candidates {
+			data.Processors[idx] = candidate.ToString()
+			if candidate.MaxConcurrency > 0 {
+				data.Limits.Processors[data.Processors[idx]] = candidate.MaxConcurrency
+			}
+		}
+		request.Data = data
+	}
 
 	// send the request to the supervisor thread
 	// the supervisor thread will:
 	//	1. create a local record of the supervisor
 	//	2. set the local record to the initial state
-	//  3. send a provision request to the processor endpoint
+	//	3. queue the supervisor if its cluster, module or processors are at their limit
+	//  4. send a provision request to the processor endpoint once it is admitted
 	thread.C13 <- request
 
 	rsp, didTimeout := multithreaded.SendAndWait(thread.SupervisorResponseTable, request.Nonce,
@@ -121,6 +144,100 @@ func (thread *Thread) updateSupervisor(r *common.ThreadRequest) error {
 	return response.Error
 }
 
+func (thread *Thread) streamSupervisor(r *common.ThreadRequest) (*supervisor.Subscription, error) {
+
+	request := common.ThreadRequest{
+		Action:      common.GetAction,
+		Type:        common.StreamRecord,
+		Identifiers: r.Identifiers,
+		Nonce:       rand.Uint32(),
+	}
+	thread.C13 <- request
+
+	rsp, didTimeout := multithreaded.SendAndWait(thread.SupervisorResponseTable, request.Nonce,
+		thread.config.Timeout)
+
+	if didTimeout {
+		return nil, multithreaded.NoResponseReceived
+	}
+
+	response := (rsp).(common.ThreadResponse)
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	return (response.Data).(*supervisor.Subscription), nil
+}
+
+func (thread *Thread) getRetention() (common.RetentionData, error) {
+
+	request := common.ThreadRequest{
+		Action: common.GetAction,
+		Type:   common.RetentionRecord,
+		Nonce:  rand.Uint32(),
+	}
+	thread.C13 <- request
+
+	rsp, didTimeout := multithreaded.SendAndWait(thread.SupervisorResponseTable, request.Nonce,
+		thread.config.Timeout)
+
+	if didTimeout {
+		return common.RetentionData{}, multithreaded.NoResponseReceived
+	}
+
+	response := (rsp).(common.ThreadResponse)
+	if response.Error != nil {
+		return common.RetentionData{}, response.Error
+	}
+
+	return (response.Data).(common.RetentionData), nil
+}
+
+func (thread *Thread) getAdmission() (common.AdmissionData, error) {
+
+	request := common.ThreadRequest{
+		Action: common.GetAction,
+		Type:   common.AdmissionRecord,
+		Nonce:  rand.Uint32(),
+	}
+	thread.C13 <- request
+
+	rsp, didTimeout := multithreaded.SendAndWait(thread.SupervisorResponseTable, request.Nonce,
+		thread.config.Timeout)
+
+	if didTimeout {
+		return common.AdmissionData{}, multithreaded.NoResponseReceived
+	}
+
+	response := (rsp).(common.ThreadResponse)
+	if response.Error != nil {
+		return common.AdmissionData{}, response.Error
+	}
+
+	return (response.Data).(common.AdmissionData), nil
+}
+
+func (thread *Thread) cancelSupervisor(r *common.ThreadRequest) error {
+
+	request := common.ThreadRequest{
+		Action:      common.CancelAction,
+		Type:        common.SupervisorRecord,
+		Identifiers: r.Identifiers,
+		Nonce:       rand.Uint32(),
+	}
+	thread.C13 <- request
+
+	rsp, didTimeout := multithreaded.SendAndWait(thread.SupervisorResponseTable, request.Nonce,
+		thread.config.Timeout)
+
+	if didTimeout {
+		return multithreaded.NoResponseReceived
+	}
+
+	response := (rsp).(common.ThreadResponse)
+	return response.Error
+}
+
 func (thread *Thread) logSupervisor(r *common.ThreadRequest) error {
 
 	request := common.ThreadRequest{
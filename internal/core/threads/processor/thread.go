@@ -54,6 +54,19 @@ func (thread *Thread) Start() {
 		}
 	}()
 
+	go func() {
+		// request coming from the supervisor thread
+		for request := range thread.C30 {
+			if !thread.accepting {
+				break
+			}
+			thread.wg.Add(1)
+
+			request.Source = common.Supervisor
+			thread.processRequest(&request)
+		}
+	}()
+
 	// RESPONSE THREADS
 
 	go func() {
@@ -105,6 +118,8 @@ func (thread *Thread) respond(source common.Module, response *common.ThreadRespo
 		thread.C8 <- *response
 	case common.Scheduler:
 		thread.C19 <- *response
+	case common.Supervisor:
+		thread.C31 <- *response
 	default:
 		return common.BadResponseType
 	}
@@ -127,6 +142,12 @@ func (thread *Thread) processRequest(request *common.ThreadRequest) {
 			response.Data, response.Error = thread.getClusters(request.Identifiers.Module)
 		case common.SupervisorRecord:
 			response.Data, response.Error = thread.getSupervisor(request)
+		case common.RetentionRecord:
+			response.Data, response.Error = thread.getRetention()
+		case common.AdmissionRecord:
+			response.Data, response.Error = thread.getAdmission()
+		case common.StreamRecord:
+			response.Data, response.Error = thread.streamSupervisor(request)
 		default:
 			response.Error = common.UnknownRequest
 		}
@@ -160,6 +181,13 @@ func (thread *Thread) processRequest(request *common.ThreadRequest) {
 		default:
 			response.Error = common.UnknownRequest
 		}
+	case common.CancelAction:
+		switch request.Type {
+		case common.SupervisorRecord:
+			response.Error = thread.cancelSupervisor(request)
+		default:
+			response.Error = common.UnknownRequest
+		}
 	case common.MountAction:
 		switch request.Type {
 		case common.ModuleRecord:
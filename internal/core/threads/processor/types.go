@@ -33,6 +33,9 @@ type Thread struct {
 	C18 <-chan common.ThreadRequest  // Processor rec req from the scheduler thread
 	C19 chan<- common.ThreadResponse // Processor sending rsp to the scheduler thread
 
+	C30 <-chan common.ThreadRequest  // Processor rec req from the supervisor thread
+	C31 chan<- common.ThreadResponse // Processor sending rsp to the supervisor thread
+
 	SupervisorResponseTable *multithreaded.ResponseTable
 	DatabaseResponseTable   *multithreaded.ResponseTable
 
@@ -114,6 +117,16 @@ func New(cfg *Config, logger *logging.Logger, channels ...any) (*Thread, error)
 		return nil, errors.New("expected type 'chan ProcessorResponse' in index 10")
 	}
 
+	thread.C30, ok = (channels[11]).(chan common.ThreadRequest)
+	if !ok {
+		return nil, errors.New("expected type 'chan ProcessorRequest' in index 11")
+	}
+
+	thread.C31, ok = (channels[12]).(chan common.ThreadResponse)
+	if !ok {
+		return nil, errors.New("expected type 'chan ProcessorResponse' in index 12")
+	}
+
 	thread.SupervisorResponseTable = multithreaded.NewResponseTable()
 	thread.DatabaseResponseTable = multithreaded.NewResponseTable()
 
@@ -9,9 +9,42 @@ var mongoUrl = os.Getenv("MONGO_DB_URL")
 
 var jobDatabase database.JobDatabase
 
-func GetJobDatabaseInstance() database.JobDatabase {
+func GetJobDatabaseInstance(t ...string) database.JobDatabase {
 	if jobDatabase == nil {
-		jobDatabase, _ = database.NewMongoJobDatabase(mongoUrl)
+
+		if len(t) == 0 || (len(t) == 1 && t[0] == "file") {
+			jobDatabase = database.NewLocalJobDatabase()
+		} else {
+			jobDatabase, _ = database.NewMongoJobDatabase(mongoUrl)
+		}
 	}
 	return jobDatabase
 }
+
+var historyDatabase database.HistoryDatabase
+
+func GetHistoryDatabaseInstance(t ...string) database.HistoryDatabase {
+	if historyDatabase == nil {
+
+		if len(t) == 0 || (len(t) == 1 && t[0] == "file") {
+			historyDatabase = database.NewLocalHistoryDatabase()
+		} else {
+			historyDatabase, _ = database.NewMongoHistoryDatabase(mongoUrl)
+		}
+	}
+	return historyDatabase
+}
+
+var blackoutDatabase database.BlackoutDatabase
+
+func GetBlackoutDatabaseInstance(t ...string) database.BlackoutDatabase {
+	if blackoutDatabase == nil {
+
+		if len(t) == 0 || (len(t) == 1 && t[0] == "file") {
+			blackoutDatabase = database.NewLocalBlackoutDatabase()
+		} else {
+			blackoutDatabase, _ = database.NewMongoBlackoutDatabase(mongoUrl)
+		}
+	}
+	return blackoutDatabase
+}
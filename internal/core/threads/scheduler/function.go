@@ -1,7 +1,13 @@
 package scheduler
 
 import (
+	"errors"
+	"fmt"
+	"github.com/GabeCordo/cluster-tools/internal/core/components/database"
+	"github.com/GabeCordo/cluster-tools/internal/core/components/scheduler"
 	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
+	"github.com/GabeCordo/cluster-tools/internal/core/threads/common"
+	"time"
 )
 
 func (thread *Thread) get(filter *interfaces.Filter) []interfaces.Job {
@@ -12,7 +18,7 @@ func (thread *Thread) get(filter *interfaces.Filter) []interfaces.Job {
 
 func (thread *Thread) create(job *interfaces.Job) error {
 
-	return thread.Scheduler.Jobs.Create(job)
+	return thread.Scheduler.Create(job)
 }
 
 func (thread *Thread) delete(filter *interfaces.Filter) error {
@@ -20,7 +26,97 @@ func (thread *Thread) delete(filter *interfaces.Filter) error {
 	return thread.Scheduler.Jobs.Delete(filter)
 }
 
-func (thread *Thread) queue() []interfaces.Job {
+func (thread *Thread) pause(identifier string, paused bool) error {
+
+	return thread.Scheduler.Jobs.SetPaused(identifier, paused)
+}
+
+func (thread *Thread) blackout(blackout *interfaces.Blackout) error {
+
+	return thread.Scheduler.Blackouts.Create(blackout)
+}
+
+func (thread *Thread) unblackout(name string) error {
+
+	return thread.Scheduler.Blackouts.Delete(name)
+}
+
+func (thread *Thread) queue() []scheduler.Trigger {
 
 	return thread.Scheduler.GetQueue()
 }
+
+func (thread *Thread) history(identifier string) ([]database.Run, error) {
+
+	if identifier == "" {
+		return nil, errors.New("an identifier is required to fetch the history of a job")
+	}
+
+	return thread.Scheduler.History.Get(identifier)
+}
+
+// upstream
+// Queues the dependents of a job once its supervisor finishes, returning the
+// identifiers of the skipped dependents that asked to be alerted.
+func (thread *Thread) upstream(identifier string, event *common.JobEventData) []string {
+
+	if err := thread.Scheduler.Complete(identifier, event.Supervisor, event.Status); err != nil {
+		thread.logger.Printf("failed to record the outcome of job:%s, %s\n", identifier, err.Error())
+	} else {
+		thread.saveHistory()
+	}
+
+	// a run replaced by a newer one says nothing about whether the job succeeded
+	if thread.Scheduler.Finished(identifier, event.Supervisor) {
+		return []string{}
+	}
+
+	queued, skipped := thread.Scheduler.Upstream(identifier, event.Completed)
+
+	for _, job := range queued {
+		thread.logger.Printf("queued job:%s after upstream job:%s\n", job.Identifier, identifier)
+	}
+
+	alerts := make([]string, 0)
+	for _, job := range skipped {
+		thread.logger.Printf("skipped job:%s after upstream job:%s did not complete\n", job.Identifier, identifier)
+		if job.GetUpstreamPolicy() == interfaces.UpstreamAlert {
+			alerts = append(alerts, job.Identifier)
+		}
+	}
+
+	return alerts
+}
+
+// admit
+// Applies the concurrency policy of the job before a supervisor is provisioned for it.
+// Returns false if the run should be skipped, the skipped run is recorded in its history.
+func (thread *Thread) admit(trigger *scheduler.Trigger) bool {
+
+	job := &trigger.Job
+
+	admit, replace := thread.Scheduler.Admit(job)
+	if !admit {
+		running := thread.Scheduler.Running(job.Identifier)
+		thread.logger.Printf("skipped job:%s, supervisors %v are still active\n", job.Identifier, running)
+
+		err := fmt.Errorf("%w (supervisors: %v)", scheduler.RunStillActive, running)
+		if e := thread.Scheduler.Skip(trigger, time.Now(), err); e != nil {
+			thread.logger.Printf("failed to record skipped run of job:%s, %s\n", job.Identifier, e.Error())
+		} else {
+			thread.saveHistory()
+		}
+		return false
+	}
+
+	mandatory := common.ThreadMandatory{thread.C18, thread.processorResponseTable, thread.config.Timeout}
+	for _, id := range replace {
+		if err := common.CancelSupervisor(mandatory, id); err != nil {
+			thread.logger.Printf("failed to cancel supervisor %d replaced by job:%s, %s\n", id, job.Identifier, err.Error())
+			continue
+		}
+		thread.logger.Printf("cancelling supervisor %d replaced by job:%s\n", id, job.Identifier)
+	}
+
+	return true
+}
@@ -7,7 +7,8 @@ import (
 	"github.com/GabeCordo/cluster-tools/internal/core/components/scheduler"
 	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
 	"github.com/GabeCordo/cluster-tools/internal/core/threads/common"
-	"github.com/GabeCordo/toolchain/multithreaded"
+	"os"
+	"time"
 )
 
 func (thread *Thread) Setup() {
@@ -22,6 +23,34 @@ func (thread *Thread) Setup() {
 			panic(err)
 		}
 	}
+
+	thread.Scheduler.Blackouts = GetBlackoutDatabaseInstance()
+	if db, ok := (thread.Scheduler.Blackouts).(database.Database); ok {
+		if err = db.Load(common.DefaultSchedulesFolder); err != nil {
+			panic(err)
+		}
+	}
+
+	// cores initialised before the run history was stored won't have the folder
+	if err = os.MkdirAll(common.DefaultHistoryFolder, 0700); err != nil {
+		thread.logger.Printf("could not create the history folder %s\n", err.Error())
+	}
+
+	thread.Scheduler.History = GetHistoryDatabaseInstance()
+	if db, ok := (thread.Scheduler.History).(database.Database); ok {
+		if err = db.Load(common.DefaultHistoryFolder); err != nil {
+			thread.logger.Printf("failed to load the run history %s\n", err.Error())
+		}
+	}
+
+	// jobs loaded from disk never went through Create, so the graph needs to be checked
+	jobs, err := thread.Scheduler.Jobs.GetAll()
+	if err != nil {
+		panic(err)
+	}
+	if err = scheduler.CheckDependencies(jobs); err != nil {
+		panic(err)
+	}
 }
 
 func (thread *Thread) Start() {
@@ -31,6 +60,16 @@ func (thread *Thread) Start() {
 	go func() {
 		for request := range thread.C20 {
 			thread.wg.Add(1)
+			request.Source = common.HttpClient
+			thread.ProcessRequest(&request)
+			thread.wg.Done()
+		}
+	}()
+
+	go func() {
+		for request := range thread.C28 {
+			thread.wg.Add(1)
+			request.Source = common.Supervisor
 			thread.ProcessRequest(&request)
 			thread.wg.Done()
 		}
@@ -52,13 +91,38 @@ func (thread *Thread) Start() {
 
 	// SCHEDULER THREADS
 
-	go scheduler.Watch(thread.Scheduler)
+	// the last fired times are saved as they move, so a crash does not repeat runs
+	go scheduler.Watch(thread.Scheduler, thread.saveJobs)
 
-	go scheduler.Loop(thread.Scheduler, func(job *interfaces.Job) error {
+	go scheduler.Loop(thread.Scheduler, func(trigger *scheduler.Trigger) error {
+
+		job := &trigger.Job
 
 		// will return have a maximum of Timeout, so worst-case takes thread.config.Timeout
 		mandatory := common.ThreadMandatory{thread.C18, thread.processorResponseTable, thread.config.Timeout}
-		_, err := common.CreateSupervisor(mandatory, job.Module, job.Cluster, job.Config, job.Metadata)
+
+		if !thread.admit(trigger) {
+			return nil
+		}
+
+		started := time.Now()
+
+		// the metadata is rendered for each run so it can reference when the run was due
+		var id uint64
+		metadata, err := trigger.Metadata()
+		if err == nil {
+			id, err = common.CreateSupervisor(mandatory, job.Module, job.Cluster, job.Config,
+				common.SupervisorRequestData{Metadata: metadata, Labels: job.Labels, Job: job.Identifier, Priority: job.Priority})
+		}
+		if err == nil {
+			thread.Scheduler.Started(job.Identifier, id)
+		}
+
+		if e := thread.Scheduler.Record(trigger, id, started, err); e != nil {
+			thread.logger.Printf("failed to record run of job:%s, %s\n", job.Identifier, e.Error())
+		} else {
+			thread.saveHistory()
+		}
 
 		e := ""
 		if err != nil {
@@ -70,21 +134,16 @@ func (thread *Thread) Start() {
 			thread.logger.Printf("%d clusters are waiting to be provisioned\n", thread.Scheduler.ItemsInQueue())
 		}
 
-		// if err is not nil, the Scheduler will stop running, so output to console
-		// if debug is enabled so the operator is aware of the runtime change
-		if ((err == processor.CanNotProvisionStreamCluster) || (err == multithreaded.NoResponseReceived)) && thread.config.Debug {
-			thread.logger.Printf("the Scheduler stopped after encountering %s\n", err.Error())
-		}
-
-		// I only care about errors that might indicate a compromised state of the threads, the others
-		// like Module/Cluster's not existing really makes no sense to crash the Scheduler as someone
-		// likely put in the job for a future module/cluster pair they want to attach to mango
-		if (err == processor.CanNotProvisionStreamCluster) || (err == multithreaded.NoResponseReceived) ||
-			(err == processor.ModuleDoesNotExist) || (err == processor.ClusterDoesNotExist) {
-			return err
-		} else {
+		// a stream cluster can never be provisioned by the scheduler, so retrying it is pointless
+		if err == processor.CanNotProvisionStreamCluster {
+			thread.logger.Printf("job:%s can not be scheduled, %s\n", job.Identifier, err.Error())
 			return nil
 		}
+
+		// any other error is returned so the Scheduler can retry the trigger according to the
+		// job's retry policy, someone likely put in the job for a future module/cluster pair,
+		// or the module is not mounted or has no processors yet
+		return err
 	})
 }
 
@@ -105,29 +164,84 @@ func (thread *Thread) ProcessRequest(request *common.ThreadRequest) {
 		case common.QueueRecord:
 			response.Data = thread.queue()
 			response.Success = true
+		case common.HistoryRecord:
+			response.Data, response.Error = thread.history(request.Identifiers.Job)
+			response.Success = response.Error == nil
+		case common.BlackoutRecord:
+			response.Data, response.Error = thread.Scheduler.Blackouts.GetAll()
+			response.Success = response.Error == nil
 		}
 
 	case common.CreateAction:
-		if job, ok := (request.Data).(interfaces.Job); ok {
-			response.Error = thread.create(&job)
+		switch data := (request.Data).(type) {
+		case interfaces.Job:
+			response.Error = thread.create(&data)
 			response.Success = response.Error == nil
-			thread.logger.Printf("created job:%s\n", job.Identifier)
-		} else {
+			if response.Success {
+				thread.logger.Printf("created job:%s\n", data.Identifier)
+				thread.saveJobs()
+			}
+		case interfaces.Blackout:
+			response.Error = thread.blackout(&data)
+			response.Success = response.Error == nil
+			if response.Success {
+				thread.logger.Printf("created blackout window %s\n", data.ToString())
+				thread.saveBlackouts()
+			}
+		default:
+			response.Success = false
+			response.Error = common.BadRequestType
+		}
+	case common.UpdateAction:
+		switch data := (request.Data).(type) {
+		case common.JobEventData:
+			response.Data = thread.upstream(request.Identifiers.Job, &data)
+			response.Success = true
+		case common.JobStateData:
+			response.Error = thread.pause(request.Identifiers.Job, data.Paused)
+			response.Success = response.Error == nil
+			if response.Success {
+				thread.logger.Printf("updated job:%s (paused: %t)\n", request.Identifiers.Job, data.Paused)
+				thread.saveJobs()
+			}
+		default:
 			response.Success = false
 			response.Error = common.BadRequestType
 		}
 	case common.DeleteAction:
-		if filter, ok := (request.Data).(interfaces.Filter); ok {
-			response.Error = thread.delete(&filter)
+		switch data := (request.Data).(type) {
+		case interfaces.Filter:
+			response.Error = thread.delete(&data)
 			response.Success = response.Error == nil
-			thread.logger.Printf("deleted job:%s\n", filter.Identifier)
-		} else {
+			if response.Success {
+				thread.logger.Printf("deleted job:%s\n", data.Identifier)
+				thread.saveJobs()
+			}
+		case string:
+			// blackout windows are identified by their name
+			response.Error = thread.unblackout(data)
+			response.Success = response.Error == nil
+			if response.Success {
+				thread.logger.Printf("deleted blackout window %s\n", data)
+				thread.saveBlackouts()
+			}
+		default:
 			response.Success = false
 			response.Error = common.BadResponseType
 		}
 	}
 
-	thread.C21 <- response
+	thread.respond(request.Source, &response)
+}
+
+func (thread *Thread) respond(source common.Module, response *common.ThreadResponse) {
+
+	switch source {
+	case common.Supervisor:
+		thread.C29 <- *response
+	default:
+		thread.C21 <- *response
+	}
 }
 
 func (thread *Thread) Teardown() {
@@ -135,10 +249,45 @@ func (thread *Thread) Teardown() {
 	// do not complete teardown until all requests have been completed
 	thread.wg.Wait()
 
+	// the last fired time of each job is saved so missed runs are detected on restart
+	thread.saveJobs()
+	thread.saveBlackouts()
+	thread.saveHistory()
+}
+
+// saveJobs
+// Writes the jobs back into the schedules folder when they are stored locally, so jobs
+// created over the api are not lost on restart and the cli sees the same schedule.
+func (thread *Thread) saveJobs() {
+
 	if db, ok := (thread.Scheduler.Jobs).(database.Database); ok {
 
 		if err := db.Save(common.DefaultSchedulesFolder); err != nil {
-			thread.logger.Panicln(err.Error())
+			thread.logger.Printf("failed to save the jobs %s\n", err.Error())
+		}
+	}
+}
+
+// saveHistory
+// Writes the run history to disk when it is stored locally, so the cli can read it while the core runs.
+func (thread *Thread) saveHistory() {
+
+	if db, ok := (thread.Scheduler.History).(database.Database); ok {
+
+		if err := db.Save(common.DefaultHistoryFolder); err != nil {
+			thread.logger.Printf("failed to save the run history %s\n", err.Error())
+		}
+	}
+}
+
+// saveBlackouts
+// Writes the blackout windows into the schedules folder when they are stored locally.
+func (thread *Thread) saveBlackouts() {
+
+	if db, ok := (thread.Scheduler.Blackouts).(database.Database); ok {
+
+		if err := db.Save(common.DefaultSchedulesFolder); err != nil {
+			thread.logger.Printf("failed to save the blackout windows %s\n", err.Error())
 		}
 	}
 }
@@ -26,6 +26,9 @@ type Thread struct {
 	C26 chan<- common.ThreadRequest  // Scheduler sends request to database_thread
 	C27 <-chan common.ThreadResponse // Scheduler receives response from database_thread
 
+	C28 <-chan common.ThreadRequest  // Scheduler receives request from supervisor thread
+	C29 chan<- common.ThreadResponse // Scheduler sends response to supervisor thread
+
 	wg sync.WaitGroup
 
 	config *Config
@@ -88,6 +91,16 @@ func New(cfg *Config, logger *logging.Logger, channels ...any) (*Thread, error)
 		return nil, errors.New("expected type 'chan DatabaseResponse' in index 6")
 	}
 
+	thread.C28, ok = (channels[7]).(chan common.ThreadRequest)
+	if !ok {
+		return nil, errors.New("expected type 'chan SchedulerRequest' in index 7")
+	}
+
+	thread.C29, ok = (channels[8]).(chan common.ThreadResponse)
+	if !ok {
+		return nil, errors.New("expected type 'chan SchedulerResponse' in index 8")
+	}
+
 	thread.processorResponseTable = multithreaded.NewResponseTable()
 	thread.databaseResponseTable = multithreaded.NewResponseTable()
 
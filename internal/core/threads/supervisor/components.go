@@ -11,3 +11,13 @@ func GetRegistryInstance() *supervisor.Registry {
 	}
 	return registryInstance
 }
+
+var admissionInstance *supervisor.Admission
+
+func GetAdmissionInstance() *supervisor.Admission {
+
+	if admissionInstance == nil {
+		admissionInstance = supervisor.NewAdmission(GetRegistryInstance())
+	}
+	return admissionInstance
+}
@@ -2,24 +2,37 @@ package supervisor
 
 import (
 	"errors"
+	"fmt"
 	"github.com/GabeCordo/cluster-tools/internal/core/api"
 	"github.com/GabeCordo/cluster-tools/internal/core/components/messenger"
 	"github.com/GabeCordo/cluster-tools/internal/core/components/supervisor"
+	"github.com/GabeCordo/cluster-tools/internal/core/interfaces"
 	"github.com/GabeCordo/cluster-tools/internal/core/threads/common"
 	"github.com/GabeCordo/toolchain/multithreaded"
 	"math/rand"
+	"time"
 )
 
-func (thread *Thread) getSupervisor(filter *supervisor.Filter) ([]*supervisor.Supervisor, error) {
+func (thread *Thread) getSupervisor(filter *supervisor.Filter) (supervisor.Page, error) {
 
 	if filter == nil {
-		return nil, errors.New("given nil pointer filter")
+		return supervisor.Page{}, errors.New("given nil pointer filter")
 	}
-	instances := GetRegistryInstance().GetBy(filter)
-	return instances, nil
+	return GetRegistryInstance().Query(filter)
 }
 
-func (thread *Thread) createSupervisor(processorName, moduleName, clusterName, configName string, metadata map[string]string) (uint64, error) {
+func (thread *Thread) createSupervisor(processorName, moduleName, clusterName, configName string, data *common.SupervisorRequestData) (uint64, error) {
+
+	// a client retrying a request it did not get a response to is given the original supervisor
+	if data.IdempotencyKey != "" {
+		if existing, found := thread.findByKey(data.IdempotencyKey, time.Now()); found {
+			if (existing.Module != moduleName) || (existing.Cluster != clusterName) || (existing.Config.Identifier != configName) {
+				return 0, supervisor.IdempotencyKeyReused
+			}
+			thread.Logger.Printf("[id: %d] returned the supervisor created with the same idempotency key\n", existing.Id)
+			return existing.Id, nil
+		}
+	}
 
 	// TODO : change it so that configs are received via pointer over the channel
 	mandatory := common.ThreadMandatory{thread.C15, thread.DatabaseResponseTable, thread.config.Timeout}
@@ -28,78 +41,564 @@ func (thread *Thread) createSupervisor(processorName, moduleName, clusterName, c
 		return 0, errors.New("no config with that identifier exists")
 	}
 
-	identifier := GetRegistryInstance().Create(processorName, moduleName, clusterName, &conf)
+	details := supervisor.Details{
+		Job:            data.Job,
+		Metadata:       data.Metadata,
+		Labels:         data.Labels,
+		Original:       data.Original,
+		Attempt:        data.Attempt,
+		Priority:       data.Priority,
+		IdempotencyKey: data.IdempotencyKey,
+	}
+	identifier := GetRegistryInstance().Provision(processorName, moduleName, clusterName, &conf, details)
 	sup, _ := GetRegistryInstance().Get(identifier)
 
-	// TODO : need to support sending the received metadata
-	err := api.ProvisionSupervisor(processorName, moduleName, clusterName, identifier, &conf, metadata)
+	// every supervisor goes through the admission queue, it is only held there if its
+	// cluster, module or every processor it can run on is at its concurrency limit
+	ticket := &supervisor.Ticket{
+		Supervisor: sup,
+		Priority:   data.Priority,
+		Queued:     time.Now(),
+		Processors: data.Processors,
+		Limits:     data.Limits,
+	}
+	if len(ticket.Processors) == 0 {
+		ticket.Processors = []string{processorName}
+	}
+	ticket.Limits.Cluster = conf.MaxConcurrency
+
+	if err := GetAdmissionInstance().Enqueue(ticket); err != nil {
+		return identifier, err
+	}
+
+	err := thread.admit(sup)
+
+	if sup.GetStatus() == supervisor.Queued {
+		thread.Logger.Printf("[id: %d][state: %s] supervisor is waiting for a slot under its concurrency limits (priority: %d)\n",
+			sup.Id, supervisor.Queued, sup.Priority)
+		thread.persist(sup)
+	}
+
+	return identifier, err
+}
+
+// findByKey
+// Returns the supervisor created with the idempotency key within the idempotency window.
+func (thread *Thread) findByKey(key string, now time.Time) (*supervisor.Supervisor, bool) {
+
+	window := thread.config.IdempotencyWindow
+	if window <= 0 {
+		window = DefaultIdempotencyWindow
+	}
+
+	return GetRegistryInstance().GetByKey(key, now.Add(-time.Duration(window*float64(time.Minute))))
+}
+
+// admit
+// Provisions the queued supervisors that fit under their concurrency limits. A supervisor
+// that waited in the queue and can not be provisioned is finished, except the one being
+// created whose error is returned to the caller instead.
+func (thread *Thread) admit(created *supervisor.Supervisor) (err error) {
+
+	for _, ticket := range GetAdmissionInstance().Admit(time.Now()) {
+
+		if ticket.Supervisor != created {
+			thread.Logger.Printf("[id: %d] admitted supervisor after waiting %s\n",
+				ticket.Supervisor.Id, time.Since(ticket.Queued).Round(time.Second))
+		}
+
+		provisionErr := thread.provision(ticket.Supervisor)
+		if ticket.Supervisor == created {
+			err = provisionErr
+		} else if provisionErr != nil {
+			if finishErr := thread.finishSupervisor(ticket.Supervisor); finishErr != nil {
+				thread.Logger.Printf("[id: %d] %s\n", ticket.Supervisor.Id, finishErr.Error())
+			}
+		}
+	}
+
+	return err
+}
+
+// provision
+// Asks the processor the supervisor was admitted to to start it, the supervisor is
+// cancelled if the processor can not be reached.
+func (thread *Thread) provision(sup *supervisor.Supervisor) error {
+
+	err := api.ProvisionSupervisor(sup.Processor, sup.Module, sup.Cluster, sup.Id, &sup.Config, sup.Metadata)
 
 	if err != nil {
 		thread.Logger.Print(err.Error())
-		thread.Logger.Printf("[core -> %s][id: %d] %s\n", processorName, sup.Id, "could not connect to the processor and supervisor is canceled")
-		sup.Status = supervisor.Cancelled
+		thread.Logger.Printf("[core -> %s][id: %d] %s\n", sup.Processor, sup.Id, "could not connect to the processor and supervisor is canceled")
+		sup.Event(supervisor.Cancel)
 	} else {
-		thread.Logger.Printf("[core -> %s][id: %d] %s\n", processorName, sup.Id, "connected to processor and supervisor is active")
-		sup.Status = supervisor.Active
+		thread.Logger.Printf("[core -> %s][id: %d] %s\n", sup.Processor, sup.Id, "connected to processor and supervisor is active")
+		sup.Event(supervisor.Start)
+		sup.Heartbeat(time.Now())
 	}
+	thread.persist(sup)
 
-	return identifier, err
+	return err
 }
 
 func (thread *Thread) updateSupervisor(instance *supervisor.Supervisor) error {
 
 	stored, found := GetRegistryInstance().Get(instance.Id)
 	if !found {
-		return errors.New("cannot update a supervisor that does not exist")
+		return supervisor.DoesNotExist
+	}
+
+	// the result is attached before the final transition so clients waiting on it find it
+	if instance.Result != nil {
+		stored.SetResult(instance.Result)
+	}
+
+	if err := stored.SetStatus(instance.Status); err != nil {
+		return err
 	}
+	stored.Heartbeat(time.Now())
 
-	stored.Status = instance.Status
-	stored.Statistics = instance.Statistics
+	// a cancellation from the core does not carry the statistics of the run
+	if instance.Statistics != nil {
+		stored.SetStatistics(instance.Statistics)
+		thread.publish(stored, supervisor.Update{Kind: supervisor.StatisticsUpdate, Statistics: instance.Statistics})
+	}
 
 	thread.Logger.Printf("[id: %d][state: %s] updated supervisor record\n", instance.Id, instance.Status)
 
-	if (stored.Status == supervisor.Completed) ||
-		(stored.Status == supervisor.Crashed) ||
-		(stored.Status == supervisor.Terminated) {
-		// TODO : this can probably encapsulate
-		request := common.ThreadRequest{
-			Action: common.CreateAction,
-			Type:   common.StatisticRecord,
-			Identifiers: common.RequestIdentifiers{
-				Module:  stored.Module,
-				Cluster: stored.Cluster,
-			},
-			Data:  stored.Statistics,
-			Nonce: rand.Uint32(),
+	if stored.IsFinished() {
+		return thread.finishSupervisor(stored)
+	}
+	thread.persist(stored)
+
+	return nil
+}
+
+// cancelSupervisor
+// Moves an active supervisor to cancelling and forwards the cancellation to the processor
+// running it. If the processor can not be reached, or does not confirm the supervisor
+// stopped before the deadline, the supervisor is terminated.
+func (thread *Thread) cancelSupervisor(id uint64) error {
+
+	stored, found := GetRegistryInstance().Get(id)
+	if !found {
+		return supervisor.DoesNotExist
+	}
+
+	// a crashed supervisor that was restarted is cancelled through its latest attempt
+	for stored.IsFinished() && (stored.RestartedByID() != 0) {
+		if stored, found = GetRegistryInstance().Get(stored.RestartedByID()); !found {
+			return supervisor.DoesNotExist
 		}
-		thread.C15 <- request
+		id = stored.Id
+	}
 
-		rsp, didTimeout := multithreaded.SendAndWait(thread.DatabaseResponseTable, request.Nonce, thread.config.Timeout)
-		if didTimeout {
-			return multithreaded.NoResponseReceived
+	if stored.IsFinished() {
+		return supervisor.NotCancellable
+	}
+
+	// the supervisor is already waiting on its processor
+	if stored.GetStatus() == supervisor.Cancelling {
+		return nil
+	}
+
+	// a supervisor that was never provisioned has nothing to stop
+	if status, _ := stored.Event(supervisor.Cancel); status == supervisor.Cancelled {
+		GetAdmissionInstance().Remove(id)
+		thread.Logger.Printf("[id: %d][state: %s] updated supervisor record\n", id, supervisor.Cancelled)
+		return thread.finishSupervisor(stored)
+	}
+
+	if err := api.CancelSupervisor(stored.Processor, id); err != nil {
+		thread.Logger.Printf("[core -> %s][id: %d] could not reach the processor to cancel the supervisor, %s\n",
+			stored.Processor, id, err.Error())
+		return thread.terminateSupervisor(id)
+	}
+
+	thread.Logger.Printf("[core -> %s][id: %d] %s\n", stored.Processor, id, "asked processor to cancel the supervisor")
+	thread.persist(stored)
+
+	deadline := thread.config.CancelTimeout
+	if deadline <= 0 {
+		deadline = DefaultCancelTimeout
+	}
+
+	time.AfterFunc(time.Duration(deadline*float64(time.Second)), func() {
+		if err := thread.terminateSupervisor(id); err != nil {
+			thread.Logger.Printf("[id: %d] %s\n", id, err.Error())
+		}
+	})
+
+	return nil
+}
+
+// terminateSupervisor
+// Marks a supervisor the processor never confirmed as cancelled as terminated.
+func (thread *Thread) terminateSupervisor(id uint64) error {
+
+	stored, found := GetRegistryInstance().Get(id)
+	if !found {
+		return supervisor.DoesNotExist
+	}
+
+	// the processor confirmed, or finished the supervisor, before the deadline
+	if _, err := stored.Event(supervisor.Terminate); err != nil {
+		return nil
+	}
+
+	thread.Logger.Printf("[id: %d][state: %s] processor did not confirm the cancellation\n", id, supervisor.Terminated)
+
+	return thread.finishSupervisor(stored)
+}
+
+// sweep
+// Crashes the running supervisors that passed the max runtime of their cluster, or whose
+// processor has gone silent, as their processor will likely never report them finished.
+func (thread *Thread) sweep(now time.Time) {
+
+	heartbeat := time.Duration(thread.config.HeartbeatTimeout * float64(time.Second))
+
+	for _, expired := range GetRegistryInstance().Expired(now, heartbeat) {
+
+		reason := expired.Expired(now, heartbeat)
+		if reason == nil {
+			continue
 		}
+		if _, err := expired.Event(supervisor.Error); err != nil {
+			continue
+		}
+
+		thread.Logger.Printf("[id: %d][state: %s] %s\n", expired.Id, supervisor.Crashed, reason.Error())
 
-		// TODO : this can also be encapsulated
-		response := (rsp).(common.ThreadResponse)
-		if !response.Success {
-			return errors.New("failed to store statistics of supervisor")
+		// the processor may still be running a supervisor that ran too long
+		if errors.Is(reason, supervisor.RuntimeExceeded) {
+			if err := api.CancelSupervisor(expired.Processor, expired.Id); err != nil {
+				thread.Logger.Printf("[core -> %s][id: %d] could not stop the supervisor, %s\n",
+					expired.Processor, expired.Id, err.Error())
+			}
 		}
 
-		msgrRequest := common.ThreadRequest{
-			Action: common.CloseAction,
+		// the reason is written to the supervisor's log before it is closed
+		thread.C17 <- common.ThreadRequest{
+			Action: common.LogAction,
+			Type:   common.FatalLogRecord,
 			Identifiers: common.RequestIdentifiers{
-				Module:     stored.Module,
-				Cluster:    stored.Cluster,
-				Supervisor: instance.Id,
+				Module:     expired.Module,
+				Cluster:    expired.Cluster,
+				Supervisor: expired.Id,
 			},
+			Data:  reason.Error(),
 			Nonce: rand.Uint32(),
 		}
-		thread.C17 <- msgrRequest
+		thread.publish(expired, supervisor.Update{Kind: supervisor.LogUpdate, Level: messenger.Fatal, Message: reason.Error()})
+
+		if err := thread.finishSupervisor(expired); err != nil {
+			thread.Logger.Printf("[id: %d] %s\n", expired.Id, err.Error())
+		}
+	}
+}
+
+// finishSupervisor
+// Stores the statistics of a supervisor that reached a terminal status, notifies the
+// scheduler if a job provisioned it and closes its log. The run is finished even if its
+// statistics could not be stored, the error is only returned once it is.
+func (thread *Thread) finishSupervisor(stored *supervisor.Supervisor) error {
+
+	thread.persist(stored)
+
+	err := thread.storeStatistics(stored)
+	if err != nil {
+		thread.Logger.Printf("[id: %d] %s\n", stored.Id, err.Error())
+	}
+
+	// dependents of a scheduled job are triggered, or skipped, once it finishes
+	// the alerts need to be logged before the supervisor's log is closed, a run
+	// that is being restarted only finishes once its last attempt does
+	restarting := thread.restartSupervisor(stored)
+	if (stored.Job != "") && !restarting {
+		thread.notifyScheduler(stored)
+	}
+
+	msgrRequest := common.ThreadRequest{
+		Action: common.CloseAction,
+		Identifiers: common.RequestIdentifiers{
+			Module:     stored.Module,
+			Cluster:    stored.Cluster,
+			Supervisor: stored.Id,
+		},
+		Nonce: rand.Uint32(),
+	}
+	thread.C17 <- msgrRequest
+
+	// the slot the supervisor held may let a queued supervisor in
+	go thread.admit(nil)
+
+	return err
+}
+
+// storeStatistics
+// Sends the statistics of the supervisor, with its labels, to the database.
+func (thread *Thread) storeStatistics(stored *supervisor.Supervisor) error {
+
+	snapshot := stored.Snapshot()
+
+	// TODO : this can probably encapsulate
+	request := common.ThreadRequest{
+		Action: common.CreateAction,
+		Type:   common.StatisticRecord,
+		Identifiers: common.RequestIdentifiers{
+			Module:  stored.Module,
+			Cluster: stored.Cluster,
+		},
+		Data:  common.StatisticData{Statistics: snapshot.Statistics, Labels: snapshot.Labels},
+		Nonce: rand.Uint32(),
+	}
+	thread.C15 <- request
+
+	rsp, didTimeout := multithreaded.SendAndWait(thread.DatabaseResponseTable, request.Nonce, thread.config.Timeout)
+	if didTimeout {
+		return multithreaded.NoResponseReceived
+	}
+
+	// TODO : this can also be encapsulated
+	response := (rsp).(common.ThreadResponse)
+	if !response.Success {
+		return errors.New("failed to store statistics of supervisor")
 	}
 
 	return nil
 }
 
+// restartSupervisor
+// Reprovisions a crashed supervisor whose cluster restarts on crash with the same module,
+// cluster, config and metadata. Attempts are made after a growing backoff, on another
+// processor if one is available, and are linked back to the original supervisor.
+func (thread *Thread) restartSupervisor(crashed *supervisor.Supervisor) bool {
+
+	if crashed.GetStatus() != supervisor.Crashed {
+		return false
+	}
+
+	attempt := crashed.Attempt + 1
+	backoff, ok := crashed.Config.RestartBackoff(attempt)
+	if !ok {
+		if crashed.Config.OnCrash == interfaces.Restart {
+			thread.Logger.Printf("[id: %d] supervisor will not be restarted, it crashed after %d restart(s)\n",
+				crashed.Id, crashed.Attempt)
+		}
+		return false
+	}
+
+	thread.Logger.Printf("[id: %d] restarting crashed supervisor in %s (attempt: %d)\n", crashed.Id, backoff, attempt)
+
+	time.AfterFunc(backoff, func() {
+
+		mandatory := common.ThreadMandatory{thread.C30, thread.ProcessorResponseTable, thread.config.Timeout}
+		data := common.SupervisorRequestData{
+			Metadata: crashed.Metadata,
+			Labels:   crashed.Labels,
+			Job:      crashed.Job,
+			Original: crashed.Origin(),
+			Attempt:  attempt,
+			Avoid:    crashed.Processor,
+			Priority: crashed.Priority,
+		}
+
+		id, err := common.CreateSupervisor(mandatory, crashed.Module, crashed.Cluster, crashed.Config.Identifier, data)
+		if id != 0 {
+			crashed.SetRestartedBy(id)
+			thread.persist(crashed)
+		}
+
+		if err != nil {
+			thread.Logger.Printf("[id: %d] could not restart crashed supervisor, %s\n", crashed.Id, err.Error())
+			// the attempt never ran, so the run of the job ends with the crash
+			if (crashed.Job != "") && (id == 0) {
+				thread.notifyScheduler(crashed)
+			}
+			return
+		}
+
+		thread.Logger.Printf("[id: %d] restarted crashed supervisor as %d\n", crashed.Id, id)
+	})
+
+	return true
+}
+
+// collect
+// Evicts the finished supervisors the retention no longer keeps from the registry. Evicted
+// supervisors are archived by the database, or deleted if archiving is turned off, so they
+// are not held in memory or restored into the registry when the core restarts.
+func (thread *Thread) collect(now time.Time) {
+
+	evicted := GetRegistryInstance().Evict(now, thread.config.Retention)
+
+	var deleted uint64
+	for _, instance := range evicted {
+		if err := thread.forget(instance.Id, thread.config.Archive); err != nil {
+			thread.Logger.Printf("[id: %d] could not remove the evicted supervisor from the database, %s\n", instance.Id, err.Error())
+			continue
+		}
+		if !thread.config.Archive {
+			deleted++
+		}
+	}
+
+	if len(evicted) > 0 {
+		thread.Logger.Printf("evicted %d finished supervisor(s) from the registry\n", len(evicted))
+	}
+
+	thread.mutex.Lock()
+	defer thread.mutex.Unlock()
+
+	thread.evicted += uint64(len(evicted))
+	thread.deleted += deleted
+	thread.collected = now
+}
+
+// forget
+// Archives, or deletes, the supervisor in the database through the database thread.
+func (thread *Thread) forget(id uint64, archive bool) error {
+
+	request := common.ThreadRequest{
+		Action:      common.DeleteAction,
+		Type:        common.SupervisorRecord,
+		Identifiers: common.RequestIdentifiers{Supervisor: id},
+		Data:        common.EvictionData{Archive: archive},
+		Nonce:       rand.Uint32(),
+	}
+	thread.C15 <- request
+
+	rsp, didTimeout := multithreaded.SendAndWait(thread.DatabaseResponseTable, request.Nonce, thread.config.Timeout)
+	if didTimeout {
+		return multithreaded.NoResponseReceived
+	}
+
+	response := (rsp).(common.ThreadResponse)
+	return response.Error
+}
+
+func (thread *Thread) getAdmission() common.AdmissionData {
+
+	now := time.Now()
+	waiting := GetAdmissionInstance().Waiting(now)
+	admitted, waited := GetAdmissionInstance().Admitted()
+
+	data := common.AdmissionData{
+		Depth:    len(waiting),
+		Admitted: admitted,
+		Waiting:  waiting,
+	}
+
+	for _, ticket := range waiting {
+		if ticket.Wait > data.LongestWait {
+			data.LongestWait = ticket.Wait
+		}
+	}
+
+	if admitted > 0 {
+		data.AverageWait = waited.Seconds() / float64(admitted)
+	}
+
+	return data
+}
+
+func (thread *Thread) getRetention() common.RetentionData {
+
+	thread.mutex.Lock()
+	defer thread.mutex.Unlock()
+
+	interval := thread.config.CollectEvery
+	if interval <= 0 {
+		interval = DefaultCollectEvery
+	}
+
+	return common.RetentionData{
+		Retention: thread.config.Retention,
+		Interval:  interval,
+		Archive:   thread.config.Archive,
+		Retained:  GetRegistryInstance().Count(),
+		Evicted:   thread.evicted,
+		Deleted:   thread.deleted,
+		Collected: thread.collected,
+	}
+}
+
+// persist
+// Stores the supervisor through the database thread so it survives the core restarting.
+func (thread *Thread) persist(instance *supervisor.Supervisor) {
+
+	mandatory := common.ThreadMandatory{thread.C15, thread.DatabaseResponseTable, thread.config.Timeout}
+	if err := common.UpdateSupervisor(mandatory, instance.Snapshot()); err != nil {
+		thread.Logger.Printf("[id: %d] could not store the supervisor, %s\n", instance.Id, err.Error())
+	}
+}
+
+// restore
+// Loads the supervisors stored before the core restarted into the registry. The ones
+// that had not finished are reconciled with their processors in the background.
+func (thread *Thread) restore() {
+
+	mandatory := common.ThreadMandatory{thread.C15, thread.DatabaseResponseTable, thread.config.Timeout}
+	data, err := common.LoadSupervisors(mandatory)
+	if err != nil {
+		thread.Logger.Printf("could not restore the supervisor registry, %s\n", err.Error())
+		return
+	}
+
+	GetRegistryInstance().Restore(data.Supervisors, data.Next)
+
+	pending := make([]*supervisor.Supervisor, 0)
+	for _, instance := range data.Supervisors {
+		if !instance.IsFinished() && (instance.GetStatus() != supervisor.Unknown) {
+			pending = append(pending, instance)
+		}
+	}
+
+	thread.Logger.Printf("restored %d supervisor(s), %d to reconcile with their processors\n",
+		len(data.Supervisors), len(pending))
+
+	go thread.reconcile(pending)
+}
+
+// reconcile
+// Keeps the restored supervisors their processors are still running, the rest are
+// marked unknown as the core can not tell how they finished.
+func (thread *Thread) reconcile(pending []*supervisor.Supervisor) {
+
+	for _, instance := range pending {
+
+		// the admission queue does not survive the core restarting
+		if instance.GetStatus() == supervisor.Queued {
+			if _, err := instance.Event(supervisor.Cancel); err != nil {
+				continue
+			}
+			thread.Logger.Printf("[id: %d][state: %s] supervisor was still queued when the core stopped\n",
+				instance.Id, supervisor.Cancelled)
+			if err := thread.finishSupervisor(instance); err != nil {
+				thread.Logger.Printf("[id: %d] %s\n", instance.Id, err.Error())
+			}
+			continue
+		}
+
+		if err := api.FindSupervisor(instance.Processor, instance.Id); err == nil {
+			// the sweeper gives the processor until the heartbeat timeout to report again
+			instance.Heartbeat(time.Now())
+			thread.Logger.Printf("[core -> %s][id: %d] processor is still running the supervisor\n",
+				instance.Processor, instance.Id)
+			continue
+		}
+
+		if _, err := instance.Event(supervisor.Lose); err != nil {
+			continue
+		}
+
+		thread.Logger.Printf("[id: %d][state: %s] processor could not confirm the supervisor after a restart\n",
+			instance.Id, supervisor.Unknown)
+		thread.persist(instance)
+	}
+}
+
 func (thread *Thread) logSupervisor(log *supervisor.Log) error {
 
 	instance, found := GetRegistryInstance().Get(log.Id)
@@ -111,6 +610,7 @@ func (thread *Thread) logSupervisor(log *supervisor.Log) error {
 	if !instance.IsRunning() {
 		return errors.New("cannot log on a supervisor that is not running")
 	}
+	instance.Heartbeat(time.Now())
 
 	// TODO : I think we can do better than this, I just want a bullet tracer
 	var logType common.RequestType
@@ -134,6 +634,84 @@ func (thread *Thread) logSupervisor(log *supervisor.Log) error {
 		Nonce: rand.Uint32(),
 	}
 	thread.C17 <- request
+	thread.publish(instance, supervisor.Update{Kind: supervisor.LogUpdate, Level: log.Level, Message: log.Message})
 
 	return nil
 }
+
+// publish
+// Pushes a statistics or log update of the supervisor to the clients following it, the
+// transitions of a supervisor are published by the supervisor itself.
+func (thread *Thread) publish(instance *supervisor.Supervisor, update supervisor.Update) {
+
+	update.Supervisor = instance.Id
+	update.Module = instance.Module
+	update.Cluster = instance.Cluster
+	update.At = time.Now()
+
+	GetRegistryInstance().Broker.Publish(update)
+}
+
+// subscribe
+// Returns a subscription to the updates of the supervisors matching the filter.
+func (thread *Thread) subscribe(filter *supervisor.Filter) (*supervisor.Subscription, error) {
+
+	if filter == nil {
+		return nil, errors.New("given nil pointer filter")
+	}
+
+	if filter.UseId() {
+		if _, found := GetRegistryInstance().Get(filter.Id); !found {
+			return nil, supervisor.DoesNotExist
+		}
+	}
+
+	return GetRegistryInstance().Broker.Subscribe(*filter), nil
+}
+
+// notifyScheduler
+// Tells the scheduler the supervisor provisioned for a job has finished. Any dependent
+// jobs that were skipped with the alert policy are raised as fatal logs on the supervisor.
+func (thread *Thread) notifyScheduler(instance *supervisor.Supervisor) {
+
+	request := common.ThreadRequest{
+		Action:      common.UpdateAction,
+		Type:        common.JobRecord,
+		Identifiers: common.RequestIdentifiers{Job: instance.Job, Supervisor: instance.Origin()},
+		Data: common.JobEventData{
+			Supervisor: instance.Origin(), // the scheduler only knows the first supervisor of a restarted run
+			Status:     string(instance.Status),
+			Completed:  instance.Status == supervisor.Completed,
+		},
+		Nonce: rand.Uint32(),
+	}
+	thread.C28 <- request
+
+	rsp, didTimeout := multithreaded.SendAndWait(thread.SchedulerResponseTable, request.Nonce, thread.config.Timeout)
+	if didTimeout {
+		thread.Logger.Printf("[id: %d] scheduler did not respond to the completion of job %s\n", instance.Id, instance.Job)
+		return
+	}
+
+	response := (rsp).(common.ThreadResponse)
+	alerts, ok := (response.Data).([]string)
+	if !ok {
+		return
+	}
+
+	for _, dependent := range alerts {
+		message := fmt.Sprintf("dependent job %s was skipped because job %s did not complete", dependent, instance.Job)
+		thread.C17 <- common.ThreadRequest{
+			Action: common.LogAction,
+			Type:   common.FatalLogRecord,
+			Identifiers: common.RequestIdentifiers{
+				Module:     instance.Module,
+				Cluster:    instance.Cluster,
+				Supervisor: instance.Id,
+			},
+			Data:  message,
+			Nonce: rand.Uint32(),
+		}
+		thread.publish(instance, supervisor.Update{Kind: supervisor.LogUpdate, Level: messenger.Fatal, Message: message})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"github.com/GabeCordo/cluster-tools/internal/core/components/supervisor"
 	"github.com/GabeCordo/cluster-tools/internal/core/threads/common"
+	"time"
 )
 
 func (thread *Thread) Setup() {
@@ -12,6 +13,33 @@ func (thread *Thread) Setup() {
 
 func (thread *Thread) Start() {
 
+	// INCOMING RESPONSES
+
+	go func() {
+		// response coming from database thread
+		for response := range thread.C16 {
+			// if this doesn't spawn its own thread we will be left waiting
+			thread.DatabaseResponseTable.Write(response.Nonce, response)
+		}
+	}()
+
+	go func() {
+		// response coming from scheduler thread
+		for response := range thread.C29 {
+			thread.SchedulerResponseTable.Write(response.Nonce, response)
+		}
+	}()
+
+	go func() {
+		// response coming from processor thread
+		for response := range thread.C31 {
+			thread.ProcessorResponseTable.Write(response.Nonce, response)
+		}
+	}()
+
+	// the registry needs to be restored before any new supervisor is given an identifier
+	thread.restore()
+
 	// INCOMING REQUESTS
 
 	go func() {
@@ -25,13 +53,52 @@ func (thread *Thread) Start() {
 		}
 	}()
 
-	// INCOMING RESPONSES
+	// SWEEPER
 
+	thread.background.Add(1)
 	go func() {
-		// response coming from database thread
-		for response := range thread.C16 {
-			// if this doesn't spawn its own thread we will be left waiting
-			thread.DatabaseResponseTable.Write(response.Nonce, response)
+		defer thread.background.Done()
+
+		ticker := time.NewTicker(DefaultSweepInterval * time.Second)
+		defer ticker.Stop()
+
+		// a supervisor whose processor died is never updated, so it is crashed once it
+		// passes its deadline instead of staying active in the registry forever
+		for {
+			select {
+			case <-thread.done:
+				return
+			case now := <-ticker.C:
+				thread.sweep(now)
+				// a supervisor that could not be finished cleanly never told the queue its slot is free
+				thread.admit(nil)
+			}
+		}
+	}()
+
+	// COLLECTOR
+
+	thread.background.Add(1)
+	go func() {
+		defer thread.background.Done()
+
+		// finished supervisors are evicted from the registry so it does not grow forever
+		interval := thread.config.CollectEvery
+		if interval <= 0 {
+			interval = DefaultCollectEvery
+		}
+
+		ticker := time.NewTicker(time.Duration(interval * float64(time.Second)))
+		defer ticker.Stop()
+
+		thread.collect(time.Now())
+		for {
+			select {
+			case <-thread.done:
+				return
+			case now := <-ticker.C:
+				thread.collect(now)
+			}
 		}
 	}()
 
@@ -57,26 +124,41 @@ func (thread *Thread) processRequest(request *common.ThreadRequest) {
 	case common.GetAction:
 		switch request.Type {
 		case common.SupervisorRecord:
+			// a query can pass a full filter, otherwise only the identifiers are used
+			f, ok := (request.Data).(supervisor.Filter)
+			if !ok {
+				f = supervisor.Filter{
+					Module:  request.Identifiers.Module,
+					Cluster: request.Identifiers.Cluster,
+					Id:      request.Identifiers.Supervisor,
+				}
+			}
+			response.Data, response.Error = thread.getSupervisor(&f)
+		case common.RetentionRecord:
+			response.Data = thread.getRetention()
+		case common.AdmissionRecord:
+			response.Data = thread.getAdmission()
+		case common.StreamRecord:
 			f := &supervisor.Filter{
 				Module:  request.Identifiers.Module,
 				Cluster: request.Identifiers.Cluster,
 				Id:      request.Identifiers.Supervisor,
 			}
-			response.Data, response.Error = thread.getSupervisor(f)
+			response.Data, response.Error = thread.subscribe(f)
 		default:
 			response.Error = common.BadRequestType
 		}
 	case common.CreateAction:
 		switch request.Type {
 		case common.SupervisorRecord:
-			metadata, success := (request.Data).(map[string]string)
+			data, success := (request.Data).(common.SupervisorRequestData)
 			if !success {
-				response.Error = errors.New("SupervisorCreate expected a map[string]string data type")
+				response.Error = errors.New("SupervisorCreate expected a common.SupervisorRequestData data type")
 			} else {
 				response.Data, response.Error = thread.createSupervisor(
 					request.Identifiers.Processor, request.Identifiers.Module,
-					request.Identifiers.Config, request.Identifiers.Config,
-					metadata)
+					request.Identifiers.Cluster, request.Identifiers.Config,
+					&data)
 			}
 		default:
 			response.Error = common.BadRequestType
@@ -89,6 +171,13 @@ func (thread *Thread) processRequest(request *common.ThreadRequest) {
 		default:
 			response.Error = common.BadRequestType
 		}
+	case common.CancelAction:
+		switch request.Type {
+		case common.SupervisorRecord:
+			response.Error = thread.cancelSupervisor(request.Identifiers.Supervisor)
+		default:
+			response.Error = common.BadRequestType
+		}
 	case common.LogAction:
 		switch request.Type {
 		case common.SupervisorRecord:
@@ -107,5 +196,11 @@ func (thread *Thread) processRequest(request *common.ThreadRequest) {
 
 func (thread *Thread) Teardown() {
 	thread.accepting = false
+
+	// a sweep or collection in progress finishes storing its supervisors before the
+	// database thread, torn down after this one, saves the registry for the last time
+	close(thread.done)
+	thread.background.Wait()
+
 	thread.wg.Wait() // don't tear down until all the requests have been processed
 }
@@ -2,15 +2,32 @@ package supervisor
 
 import (
 	"errors"
+	"github.com/GabeCordo/cluster-tools/internal/core/components/supervisor"
 	"github.com/GabeCordo/cluster-tools/internal/core/threads/common"
 	"github.com/GabeCordo/toolchain/logging"
 	"github.com/GabeCordo/toolchain/multithreaded"
 	"sync"
+	"time"
+)
+
+const (
+	DefaultCancelTimeout = 30 // seconds
+	DefaultSweepInterval = 10 // seconds
+	DefaultCollectEvery  = 60 // seconds
+
+	DefaultIdempotencyWindow = 1440 // minutes
 )
 
 type Config struct {
-	Debug   bool
-	Timeout float64
+	Debug            bool
+	Timeout          float64
+	CancelTimeout    float64 // seconds the processor has to confirm a supervisor was cancelled
+	HeartbeatTimeout float64 // seconds a processor can be silent before its supervisor is crashed, 0 to disable
+	Retention        supervisor.Retention
+	CollectEvery     float64 // seconds between evicting finished supervisors the retention no longer keeps
+	Archive          bool    // evicted supervisors are archived on disk instead of being deleted
+
+	IdempotencyWindow float64 // minutes a supervisor is returned again for a create request with its idempotency key
 }
 
 type Thread struct {
@@ -24,13 +41,31 @@ type Thread struct {
 
 	C17 chan common.ThreadRequest // supervisor sends requests to the messenger
 
+	C28 chan common.ThreadRequest  // supervisor sends requests to the scheduler
+	C29 chan common.ThreadResponse // supervisor receives responses from the scheduler
+
+	C30 chan common.ThreadRequest  // supervisor sends requests to the processor
+	C31 chan common.ThreadResponse // supervisor receives responses from the processor
+
 	config *Config
 	Logger *logging.Logger
 
-	DatabaseResponseTable *multithreaded.ResponseTable
+	DatabaseResponseTable  *multithreaded.ResponseTable
+	SchedulerResponseTable *multithreaded.ResponseTable
+	ProcessorResponseTable *multithreaded.ResponseTable
+
+	// how many supervisors were evicted, and deleted, since the core started
+	evicted   uint64
+	deleted   uint64
+	collected time.Time
+	mutex     sync.Mutex
 
 	accepting bool
 	wg        sync.WaitGroup
+
+	// stops the sweeper and the collector, which are waited on before the thread is torn down
+	done       chan struct{}
+	background sync.WaitGroup
 }
 
 func NewThread(cfg *Config, logger *logging.Logger, channels ...any) (*Thread, error) {
@@ -73,8 +108,28 @@ func NewThread(cfg *Config, logger *logging.Logger, channels ...any) (*Thread, e
 	if !ok {
 		return nil, errors.New("expected type 'chan MessengerRequest' in index 7")
 	}
+	thread.C28, ok = (channels[6]).(chan common.ThreadRequest)
+	if !ok {
+		return nil, errors.New("expected type 'chan SchedulerRequest' in index 6")
+	}
+	thread.C29, ok = (channels[7]).(chan common.ThreadResponse)
+	if !ok {
+		return nil, errors.New("expected type 'chan SchedulerResponse' in index 7")
+	}
+	thread.C30, ok = (channels[8]).(chan common.ThreadRequest)
+	if !ok {
+		return nil, errors.New("expected type 'chan ProcessorRequest' in index 8")
+	}
+	thread.C31, ok = (channels[9]).(chan common.ThreadResponse)
+	if !ok {
+		return nil, errors.New("expected type 'chan ProcessorResponse' in index 9")
+	}
 
 	thread.DatabaseResponseTable = multithreaded.NewResponseTable()
+	thread.SchedulerResponseTable = multithreaded.NewResponseTable()
+	thread.ProcessorResponseTable = multithreaded.NewResponseTable()
+
+	thread.done = make(chan struct{})
 
 	return thread, nil
 }
@@ -28,6 +28,11 @@ func main() {
 	stc := cli.AddCommand("stats", controllers.StatisticsController{})
 	stc.SetCategory("data").SetDescription("used to view the statistic files stored on the system")
 
+	hc := cli.AddCommand("history", controllers.HistoryController{})
+	hc.SetCategory("data").SetDescription(
+		"used to view the runs triggered by the scheduler" +
+			"\n\t\t[identifier] specify the job to output every run of")
+
 	sc := cli.AddCommand("start", controllers.StartCommand{})
 	sc.SetCategory("utils").SetDescription("start the core on the local system")
 
@@ -35,7 +40,11 @@ func main() {
 	rc.SetCategory("utils").SetDescription("enable or disable the repl when running mango start")
 
 	shc := cli.AddCommand("schedule", controllers.ScheduleController{})
-	shc.SetCategory("utils").SetDescription("create or delete schedules for when clusters should be provisioned")
+	shc.SetCategory("utils").SetDescription(
+		"create or delete schedules for when clusters should be provisioned" +
+			"\n\t\t[list] output every job in the static schedule" +
+			"\n\t\t[validate] check the static schedule for problems before the core loads it" +
+			"\n\t\t[next] [--count N] output the next N times each job will run")
 
 	cc := cli.AddCommand("config", controllers.ConfigCommand{})
 	cc.SetCategory("utils").SetDescription("update the global cluster-tools configuration")